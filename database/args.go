@@ -0,0 +1,142 @@
+package database
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// serializedArg is an argument tagged with its type, so it decodes to exactly what was bound
+type serializedArg struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// MarshalArgs serializes query arguments with type tags, so captured statements can be replayed
+// with the same values: NULL, []byte, time.Time (with its offset), time.Duration and UUID survive
+// the round trip. Other integers and floats are widened to int64, uint64 and float64 as the driver
+// would send them, and driver.Valuer arguments are stored as the value they bind
+func MarshalArgs(args []interface{}) ([]byte, error) {
+	serialized := make([]serializedArg, 0, len(args))
+	for i, arg := range args {
+		tagged, err := tagArg(arg)
+		if err != nil {
+			return nil, fmt.Errorf("argument %d: %s", i, err.Error())
+		}
+		serialized = append(serialized, tagged)
+	}
+	return json.Marshal(serialized)
+}
+
+// UnmarshalArgs decodes query arguments serialized by MarshalArgs
+func UnmarshalArgs(data []byte) ([]interface{}, error) {
+	var serialized []serializedArg
+	err := json.Unmarshal(data, &serialized)
+	if err != nil {
+		return nil, err
+	}
+	args := make([]interface{}, 0, len(serialized))
+	for i, tagged := range serialized {
+		arg, err := untagArg(tagged)
+		if err != nil {
+			return nil, fmt.Errorf("argument %d: %s", i, err.Error())
+		}
+		args = append(args, arg)
+	}
+	return args, nil
+}
+
+func tagArg(arg interface{}) (serializedArg, error) {
+	var tag string
+	var value interface{}
+	switch typed := arg.(type) {
+	case nil:
+		return serializedArg{Type: "null"}, nil
+	case UUID:
+		tag, value = "uuid", typed.String()
+	case time.Time:
+		tag, value = "time", typed.Format(time.RFC3339Nano)
+	case time.Duration:
+		tag, value = "duration", int64(typed)
+	case []byte:
+		tag, value = "bytes", typed
+	case string:
+		tag, value = "string", typed
+	case bool:
+		tag, value = "bool", typed
+	case driver.Valuer:
+		bound, err := typed.Value()
+		if err != nil {
+			return serializedArg{}, err
+		}
+		return tagArg(bound)
+	default:
+		number := reflect.ValueOf(arg)
+		switch number.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			tag, value = "int64", number.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			tag, value = "uint64", number.Uint()
+		case reflect.Float32, reflect.Float64:
+			tag, value = "float64", number.Float()
+		default:
+			return serializedArg{}, fmt.Errorf("cannot serialize %T", arg)
+		}
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return serializedArg{}, err
+	}
+	return serializedArg{Type: tag, Value: raw}, nil
+}
+
+func untagArg(tagged serializedArg) (interface{}, error) {
+	var err error
+	switch tagged.Type {
+	case "null":
+		return nil, nil
+	case "uuid":
+		var text string
+		if err = json.Unmarshal(tagged.Value, &text); err != nil {
+			return nil, err
+		}
+		return ParseUUID(text)
+	case "time":
+		var text string
+		if err = json.Unmarshal(tagged.Value, &text); err != nil {
+			return nil, err
+		}
+		return time.Parse(time.RFC3339Nano, text)
+	case "duration":
+		var nanos int64
+		err = json.Unmarshal(tagged.Value, &nanos)
+		return time.Duration(nanos), err
+	case "bytes":
+		var value []byte
+		err = json.Unmarshal(tagged.Value, &value)
+		return value, err
+	case "string":
+		var value string
+		err = json.Unmarshal(tagged.Value, &value)
+		return value, err
+	case "bool":
+		var value bool
+		err = json.Unmarshal(tagged.Value, &value)
+		return value, err
+	case "int64":
+		var value int64
+		err = json.Unmarshal(tagged.Value, &value)
+		return value, err
+	case "uint64":
+		var value uint64
+		err = json.Unmarshal(tagged.Value, &value)
+		return value, err
+	case "float64":
+		var value float64
+		err = json.Unmarshal(tagged.Value, &value)
+		return value, err
+	}
+	return nil, fmt.Errorf("unknown argument type %s", tagged.Type)
+}
@@ -0,0 +1,69 @@
+package database
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMarshalArgs(t *testing.T) {
+	uuid, err := NewUUID()
+	if err != nil {
+		t.Error(err)
+	}
+	at := time.Date(2024, time.June, 1, 12, 30, 0, 123456789, time.FixedZone("+02:00", 2*60*60))
+	args := []interface{}{
+		nil,
+		"text",
+		[]byte{0, 1, 255},
+		int64(9007199254740993),
+		uint64(18446744073709551615),
+		0.1,
+		true,
+		at,
+		90 * time.Minute,
+		uuid,
+	}
+	data, err := MarshalArgs(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := UnmarshalArgs(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != len(args) {
+		t.Fatalf("expected %d arguments, got %d", len(args), len(decoded))
+	}
+	for i, arg := range args {
+		if at, ok := arg.(time.Time); ok {
+			decodedAt, _ := decoded[i].(time.Time)
+			if !decodedAt.Equal(at) || decodedAt.Format(time.RFC3339Nano) != at.Format(time.RFC3339Nano) {
+				t.Errorf("expected %v, got %v", at, decoded[i])
+			}
+			continue
+		}
+		if !reflect.DeepEqual(decoded[i], arg) {
+			t.Errorf("expected %v (%T), got %v (%T)", arg, arg, decoded[i], decoded[i])
+		}
+	}
+}
+
+func TestMarshalArgsWidens(t *testing.T) {
+	data, err := MarshalArgs([]interface{}{7, float32(1.5), sql.NullString{String: "valued", Valid: true}, sql.NullInt64{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := UnmarshalArgs(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []interface{}{int64(7), 1.5, "valued", nil}
+	if !reflect.DeepEqual(decoded, expected) {
+		t.Errorf("expected %v, got %v", expected, decoded)
+	}
+	if _, err = MarshalArgs([]interface{}{struct{}{}}); err == nil {
+		t.Errorf("expected an error for an argument that cannot be serialized")
+	}
+}
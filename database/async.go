@@ -0,0 +1,205 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrAsyncQueueFull is returned by ExecAsync when the write-behind buffer has no room left
+var ErrAsyncQueueFull = errors.New("async queue is full")
+
+// ErrAsyncNotEnabled is returned by ExecAsync when EnableAsync has not been called
+var ErrAsyncNotEnabled = errors.New("async writes are not enabled")
+
+// AsyncOptions configures the write-behind queue used by ExecAsync
+type AsyncOptions struct {
+	// BufferSize is the number of statements that can be queued; defaults to 1000
+	BufferSize int
+	// BatchSize is the number of statements written per transaction; defaults to 100
+	BatchSize int
+	// FlushInterval is how often a partial batch is written; defaults to one second
+	FlushInterval time.Duration
+	// OnError is called for each statement that could not be written
+	OnError func(query string, inserts []interface{}, err error)
+}
+
+type asyncStatement struct {
+	query   string
+	inserts []interface{}
+	// insertID is the AUTO_INCREMENT id an insert should use, when mirrored from a primary
+	insertID int64
+	// traceID tags the statement as WithTraceID does, when mirrored from a traced write
+	traceID string
+}
+
+type asyncQueue struct {
+	mu         sync.Mutex
+	closed     bool
+	database   *Database
+	options    AsyncOptions
+	statements chan asyncStatement
+	flush      chan chan struct{}
+	done       chan struct{}
+}
+
+// EnableAsync starts the write-behind queue for low-value writes. Queued statements are
+// written in batches and flushed when the database is closed, but are lost if the process crashes
+func (d *Database) EnableAsync(options AsyncOptions) {
+	if d.async != nil {
+		d.async.stop()
+	}
+	if options.BufferSize < 1 {
+		options.BufferSize = 1000
+	}
+	if options.BatchSize < 1 {
+		options.BatchSize = 100
+	}
+	if options.FlushInterval <= 0 {
+		options.FlushInterval = time.Second
+	}
+	d.async = &asyncQueue{
+		database:   d,
+		options:    options,
+		statements: make(chan asyncStatement, options.BufferSize),
+		flush:      make(chan chan struct{}),
+		done:       make(chan struct{}),
+	}
+	go d.async.run()
+}
+
+// ExecAsync queues a statement to be executed in the background without waiting for it
+func (d *Database) ExecAsync(query string, inserts []interface{}) error {
+	return d.execAsync(asyncStatement{query: query, inserts: inserts})
+}
+
+func (d *Database) execAsync(statement asyncStatement) error {
+	if d.async == nil {
+		return ErrAsyncNotEnabled
+	}
+	return d.async.push(statement)
+}
+
+// FlushAsync blocks until every statement queued so far has been written
+func (d *Database) FlushAsync() {
+	q := d.async
+	if q == nil {
+		return
+	}
+	// q.mu isn't held while waiting, as the worker may need it to requeue a statement from OnError
+	flushed := make(chan struct{})
+	select {
+	case q.flush <- flushed:
+		<-flushed
+	case <-q.done:
+		// the queue has been stopped, which writes what it held
+	}
+}
+
+func (q *asyncQueue) push(statement asyncStatement) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return ErrAsyncNotEnabled
+	}
+	select {
+	case q.statements <- statement:
+		return nil
+	default:
+		return ErrAsyncQueueFull
+	}
+}
+
+// stops accepting statements and waits for the queue to drain
+func (q *asyncQueue) stop() {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return
+	}
+	q.closed = true
+	close(q.statements)
+	q.mu.Unlock()
+	<-q.done
+}
+
+func (q *asyncQueue) run() {
+	defer close(q.done)
+	ticker := time.NewTicker(q.options.FlushInterval)
+	defer ticker.Stop()
+	batch := make([]asyncStatement, 0, q.options.BatchSize)
+	for {
+		select {
+		case statement, ok := <-q.statements:
+			if !ok {
+				q.write(batch)
+				return
+			}
+			batch = append(batch, statement)
+			if len(batch) >= q.options.BatchSize {
+				q.write(batch)
+				batch = batch[:0]
+			}
+		case flushed := <-q.flush:
+			for len(q.statements) > 0 {
+				batch = append(batch, <-q.statements)
+			}
+			q.write(batch)
+			batch = batch[:0]
+			close(flushed)
+		case <-ticker.C:
+			q.write(batch)
+			batch = batch[:0]
+		}
+	}
+}
+
+// writes a batch of statements in a single transaction
+func (q *asyncQueue) write(batch []asyncStatement) {
+	if len(batch) < 1 {
+		return
+	}
+	connection, release := q.database.acquire()
+	defer release()
+	tx, err := connection.Begin()
+	if err != nil {
+		q.fail(batch, err)
+		return
+	}
+	// statements that failed are reported once, and not again if the commit fails
+	unreported := make([]asyncStatement, 0, len(batch))
+	for _, statement := range batch {
+		ctx := context.Background()
+		if len(statement.traceID) > 0 {
+			ctx = WithTraceID(ctx, statement.traceID)
+		}
+		if statement.insertID > 0 {
+			_, err = tx.ExecContext(ctx, fmt.Sprintf("SET insert_id = %d", statement.insertID))
+			if err != nil {
+				q.fail([]asyncStatement{statement}, err)
+				continue
+			}
+		}
+		_, err = q.database.exec(ctx, tx, statement.query, statement.inserts)
+		if err != nil {
+			q.fail([]asyncStatement{statement}, err)
+			continue
+		}
+		unreported = append(unreported, statement)
+	}
+	err = tx.Commit()
+	if err != nil {
+		q.fail(unreported, err)
+	}
+}
+
+func (q *asyncQueue) fail(batch []asyncStatement, err error) {
+	if q.options.OnError == nil {
+		return
+	}
+	for _, statement := range batch {
+		q.options.OnError(statement.query, statement.inserts, err)
+	}
+}
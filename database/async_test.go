@@ -0,0 +1,114 @@
+package database
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestExecAsync(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	d, err := Make(getConfigs(false))
+	if err != nil {
+		t.Error(err)
+	}
+	defer d.Close()
+	err = d.ExecAsync("insert into widgets (sku, description, weight) values (?, ?, ?)", []interface{}{"ASYNC0", "Async Widget", 1.0})
+	if !errors.Is(err, ErrAsyncNotEnabled) {
+		t.Errorf("expected ErrAsyncNotEnabled, got %v", err)
+	}
+	var mu sync.Mutex
+	var failed []string
+	d.EnableAsync(AsyncOptions{
+		BatchSize:     2,
+		FlushInterval: time.Hour,
+		OnError: func(query string, inserts []interface{}, err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			failed = append(failed, query)
+		},
+	})
+	for _, sku := range []string{"ASYNC1", "ASYNC2", "ASYNC3"} {
+		err = d.ExecAsync("insert into widgets (sku, description, weight) values (?, ?, ?)", []interface{}{sku, "Async Widget", 1.0})
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	err = d.ExecAsync("insert into no_such_table (sku) values (?)", []interface{}{"ASYNC4"})
+	if err != nil {
+		t.Error(err)
+	}
+	d.FlushAsync()
+	for _, sku := range []string{"ASYNC1", "ASYNC2", "ASYNC3"} {
+		checkWidgetExists(t, sku)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(failed) != 1 {
+		t.Errorf("expected one failed statement, got %d", len(failed))
+	}
+}
+
+func TestFlushAsyncWhileRequeueing(t *testing.T) {
+	defer recovery(t)
+	d, err := Make(getConfigs(false))
+	if err != nil {
+		t.Error(err)
+	}
+	defer d.Close()
+	failing := make(chan struct{})
+	var once sync.Once
+	d.EnableAsync(AsyncOptions{
+		FlushInterval: 10 * time.Millisecond,
+		OnError: func(query string, inserts []interface{}, err error) {
+			once.Do(func() {
+				close(failing)
+				// give FlushAsync time to start waiting, then requeue the statement
+				time.Sleep(50 * time.Millisecond)
+				d.ExecAsync(query, inserts)
+			})
+		},
+	})
+	err = d.ExecAsync("insert into no_such_table (sku) values (?)", []interface{}{"REQUEUED"})
+	if err != nil {
+		t.Error(err)
+	}
+	<-failing
+	flushed := make(chan struct{})
+	go func() {
+		d.FlushAsync()
+		close(flushed)
+	}()
+	select {
+	case <-flushed:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected FlushAsync not to deadlock with a statement requeued from OnError")
+	}
+}
+
+func TestExecAsyncNormalizes(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	configs := getConfigs(false)
+	configs.Normalizer = composeAcute
+	d, err := Make(configs)
+	if err != nil {
+		t.Error(err)
+	}
+	defer d.Close()
+	d.EnableAsync(AsyncOptions{FlushInterval: time.Hour})
+	err = d.ExecAsync("insert into widgets (sku, description) values (?, ?)", []interface{}{"ASYNC5", "Asynce\u0301"})
+	if err != nil {
+		t.Error(err)
+	}
+	d.FlushAsync()
+	rows, err := tdb.QueryRaw("select description from widgets where sku = ?", []interface{}{"ASYNC5"})
+	if err != nil {
+		t.Error(err)
+	}
+	if len(rows) != 1 || rows[0]["description"] != "Async\u00e9" {
+		t.Errorf("expected the async write's arguments to be normalized, got %v", rows)
+	}
+}
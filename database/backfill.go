@@ -0,0 +1,43 @@
+package database
+
+import (
+	"errors"
+	"strings"
+)
+
+// InsertSource is the SELECT that InsertMissing copies rows from. Its result columns must be
+// named after the target's columns they fill
+type InsertSource struct {
+	Query   string
+	Args    []interface{}
+	Columns []string
+}
+
+// InsertMissing copies the source's rows into the target table, skipping rows whose unique columns
+// already match a row in the target. NULLs match each other. Rows repeated in the source are
+// inserted once. It returns the number of rows inserted
+func (d *Database) InsertMissing(target string, source InsertSource, uniqueColumns []string) (int64, error) {
+	if len(source.Columns) < 1 {
+		return 0, errors.New("the insert source has no columns")
+	}
+	if len(uniqueColumns) < 1 {
+		return 0, errors.New("no unique columns to match existing rows on")
+	}
+	table := quoteIdentifier(d.Name()) + "." + quoteIdentifier(target)
+	var matches []string
+	for _, column := range uniqueColumns {
+		matches = append(matches, "existing."+quoteIdentifier(column)+" <=> source."+quoteIdentifier(column))
+	}
+	var selected []string
+	for _, column := range source.Columns {
+		selected = append(selected, "source."+quoteIdentifier(column))
+	}
+	query := "INSERT INTO " + table + " (" + quoteIdentifiers(source.Columns) + ") " +
+		"SELECT DISTINCT " + strings.Join(selected, ", ") + " FROM (" + source.Query + ") AS source " +
+		"WHERE NOT EXISTS (SELECT 1 FROM " + table + " AS existing WHERE " + strings.Join(matches, " AND ") + ")"
+	result, err := d.Exec(query, source.Args)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
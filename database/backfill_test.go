@@ -0,0 +1,54 @@
+package database
+
+import (
+	"testing"
+)
+
+func TestInsertMissing(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	_, err := tdb.Exec("CREATE TABLE IF NOT EXISTS widget_imports (sku VARCHAR(100), description VARCHAR(100), weight FLOAT)", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	_, err = tdb.MakeRecord(map[string]interface{}{"sku": "BACKFILL1", "description": "Existing Widget"}, "widgets").Create()
+	if err != nil {
+		t.Error(err)
+	}
+	imports := []map[string]interface{}{
+		{"sku": "BACKFILL1", "description": "Imported Widget", "weight": 1.0},
+		{"sku": "BACKFILL2", "description": "Imported Widget", "weight": 2.0},
+		{"sku": "BACKFILL2", "description": "Imported Widget", "weight": 2.0},
+		{"sku": "BACKFILL3", "description": "Ignored Widget", "weight": 3.0},
+	}
+	_, err = tdb.MakeRecords(imports, "widget_imports").Create()
+	if err != nil {
+		t.Error(err)
+	}
+	source := InsertSource{
+		Query:   "SELECT sku, description, weight FROM widget_imports WHERE description = ?",
+		Args:    []interface{}{"Imported Widget"},
+		Columns: []string{"sku", "description", "weight"},
+	}
+	count, err := tdb.InsertMissing("widgets", source, []string{"sku"})
+	if err != nil {
+		t.Error(err)
+	}
+	if count != 1 {
+		t.Errorf("expected one row to be inserted, got %d", count)
+	}
+	count, err = tdb.InsertMissing("widgets", source, []string{"sku"})
+	if err != nil {
+		t.Error(err)
+	}
+	if count != 0 {
+		t.Errorf("expected a repeated backfill to insert nothing, got %d", count)
+	}
+	rows, err := tdb.QueryRaw("select description from widgets where sku = ?", []interface{}{"BACKFILL1"})
+	if err != nil {
+		t.Error(err)
+	}
+	if len(rows) != 1 || rows[0]["description"] != "Existing Widget" {
+		t.Errorf("expected the existing row to be left alone, got %v", rows)
+	}
+}
@@ -0,0 +1,130 @@
+package database
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// defaultCanaryTimeout bounds a sampled call's new query when CanaryOptions.Timeout is unset
+const defaultCanaryTimeout = 30 * time.Second
+
+// CanaryOptions configures how a Canary compares a rewritten query with the one it replaces
+type CanaryOptions struct {
+	// Rate is the fraction of calls, between 0 and 1, that also run the new query
+	Rate float64
+	// KeyColumns match rows between the results; defaults to every column of the old result
+	KeyColumns []string
+	// MaxSlowdown reports the new query as divergent when it takes longer than the old one by more
+	// than this; zero ignores latency
+	MaxSlowdown time.Duration
+	// Timeout cancels a sampled call's new query, which is reported as failed; defaults to 30 seconds
+	Timeout time.Duration
+	// OnDivergence is called for each sampled call whose new query failed, returned different rows,
+	// or was too slow
+	OnDivergence func(report CanaryReport)
+}
+
+// CanaryReport is the comparison of a sampled call's old and new queries
+type CanaryReport struct {
+	Args        []interface{}
+	Diff        ResultDiff
+	OldDuration time.Duration
+	NewDuration time.Duration
+	Err         error
+}
+
+// Canary runs a query rewrite side by side with the query it replaces for a sample of calls
+type Canary struct {
+	database *Database
+	oldQuery string
+	newQuery string
+	options  CanaryOptions
+	running  sync.WaitGroup
+}
+
+// Canary makes a Canary for shipping a query rewrite safely. Its callers always get the old query's
+// results; sampled calls also run the new query in the background, in the Background lane, and
+// compare the two
+func (d *Database) Canary(oldQuery, newQuery string, options CanaryOptions) *Canary {
+	if options.Timeout <= 0 {
+		options.Timeout = defaultCanaryTimeout
+	}
+	return &Canary{
+		database: d,
+		oldQuery: oldQuery,
+		newQuery: newQuery,
+		options:  options,
+	}
+}
+
+// Query runs the old query, returning its rows, and for sampled calls starts the new one without
+// waiting for it
+func (c *Canary) Query(args []interface{}) ([]map[string]interface{}, error) {
+	started := time.Now()
+	rows, err := c.database.QueryRaw(c.oldQuery, args)
+	if err != nil {
+		return nil, err
+	}
+	if c.options.Rate <= 0 || rand.Float64() >= c.options.Rate {
+		return rows, nil
+	}
+	// the comparison gets its own copies, as the caller is free to change what it is returned
+	report := CanaryReport{Args: append([]interface{}{}, args...), OldDuration: time.Since(started)}
+	old := make([]map[string]interface{}, len(rows))
+	for i, row := range rows {
+		old[i] = make(map[string]interface{}, len(row))
+		for column, value := range row {
+			old[i][column] = value
+		}
+	}
+	c.running.Add(1)
+	go func() {
+		defer c.running.Done()
+		c.compare(old, report)
+	}()
+	return rows, nil
+}
+
+// Wait blocks until the new queries of sampled calls have finished and been compared
+func (c *Canary) Wait() {
+	c.running.Wait()
+}
+
+// runs the new query and reports it when it diverges from the old query's rows
+func (c *Canary) compare(old []map[string]interface{}, report CanaryReport) {
+	started := time.Now()
+	canary, err := c.database.QueryRawWithOptions(c.newQuery, report.Args, QueryOptions{
+		Timeout:  c.options.Timeout,
+		Priority: Background,
+	})
+	report.NewDuration = time.Since(started)
+	if err != nil {
+		report.Err = err
+	} else {
+		report.Diff = DiffResults(old, canary, c.keyColumns(old))
+	}
+	if report.diverged(c.options.MaxSlowdown) && c.options.OnDivergence != nil {
+		c.options.OnDivergence(report)
+	}
+}
+
+func (c *Canary) keyColumns(rows []map[string]interface{}) []string {
+	if len(c.options.KeyColumns) > 0 || len(rows) < 1 {
+		return c.options.KeyColumns
+	}
+	columns := make([]string, 0, len(rows[0]))
+	for column := range rows[0] {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+func (r CanaryReport) diverged(maxSlowdown time.Duration) bool {
+	if r.Err != nil || !r.Diff.Equal() {
+		return true
+	}
+	return maxSlowdown > 0 && r.NewDuration-r.OldDuration > maxSlowdown
+}
@@ -0,0 +1,77 @@
+package database
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCanaryReportDiverged(t *testing.T) {
+	report := CanaryReport{OldDuration: time.Millisecond, NewDuration: 5 * time.Millisecond}
+	if report.diverged(0) {
+		t.Errorf("expected matching results to agree when latency is ignored")
+	}
+	if !report.diverged(2 * time.Millisecond) {
+		t.Errorf("expected a slower new query to diverge")
+	}
+	report.Err = errors.New("syntax error")
+	if !report.diverged(0) {
+		t.Errorf("expected a failed new query to diverge")
+	}
+}
+
+func TestCanary(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	var reports []CanaryReport
+	onDivergence := func(report CanaryReport) {
+		reports = append(reports, report)
+	}
+	same := tdb.Canary(
+		"select sku, weight from widgets where sku = ?",
+		"select weight, sku from widgets where sku in (?)",
+		CanaryOptions{Rate: 1, KeyColumns: []string{"sku"}, OnDivergence: onDivergence},
+	)
+	rows, err := same.Query([]interface{}{"WIDG1"})
+	if err != nil {
+		t.Error(err)
+	}
+	same.Wait()
+	if len(rows) != 1 || len(reports) != 0 {
+		t.Errorf("expected equivalent queries not to diverge, got %v", reports)
+	}
+	different := tdb.Canary(
+		"select sku from widgets where sku = ?",
+		"select sku from widgets where sku <> ?",
+		CanaryOptions{Rate: 1, OnDivergence: onDivergence},
+	)
+	rows, err = different.Query([]interface{}{"WIDG1"})
+	if err != nil {
+		t.Error(err)
+	}
+	rows[0]["sku"] = "CHANGED"
+	different.Wait()
+	if len(rows) != 1 {
+		t.Errorf("expected the old query's rows, got %v", rows)
+	}
+	if len(reports) != 1 || len(reports[0].Diff.Removed) != 1 || reports[0].Diff.Removed[0]["sku"] != "WIDG1" {
+		t.Errorf("expected the rewrite to be reported, got %v", reports)
+	}
+	slow := tdb.Canary(
+		"select sku from widgets where sku = ?",
+		"select sku, sleep(1) as slept from widgets where sku = ?",
+		CanaryOptions{Rate: 1, Timeout: 50 * time.Millisecond, OnDivergence: onDivergence},
+	)
+	started := time.Now()
+	_, err = slow.Query([]interface{}{"WIDG1"})
+	if err != nil {
+		t.Error(err)
+	}
+	if time.Since(started) > 500*time.Millisecond {
+		t.Errorf("expected the caller not to wait for the new query")
+	}
+	slow.Wait()
+	if len(reports) != 2 || reports[1].Err == nil {
+		t.Errorf("expected the timed out new query to be reported as failed, got %v", reports)
+	}
+}
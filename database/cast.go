@@ -0,0 +1,138 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Cast converts a column's values between how a Record holds them and how they are stored.
+// Hydrate runs on rows a Record is loaded from and Serialize on properties a Record writes
+type Cast struct {
+	Hydrate   func(value interface{}) (interface{}, error)
+	Serialize func(value interface{}) (interface{}, error)
+}
+
+// CastJSON holds a JSON column's document decoded, e.g. as a map[string]interface{}, and writes it encoded
+var CastJSON = Cast{
+	Hydrate: func(value interface{}) (interface{}, error) {
+		var raw []byte
+		switch stored := value.(type) {
+		case []byte:
+			raw = stored
+		case string:
+			raw = []byte(stored)
+		default:
+			return value, nil
+		}
+		if len(raw) == 0 {
+			// a NULL column reads as an empty string unless NullAsNil is set
+			return nil, nil
+		}
+		var document interface{}
+		err := json.Unmarshal(raw, &document)
+		return document, err
+	},
+	Serialize: func(value interface{}) (interface{}, error) {
+		switch value.(type) {
+		case string, []byte:
+			return value, nil
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		return string(encoded), nil
+	},
+}
+
+// CastBool holds an integer column as a bool, writing 1 or 0
+var CastBool = Cast{
+	Hydrate: func(value interface{}) (interface{}, error) {
+		switch stored := value.(type) {
+		case bool:
+			return stored, nil
+		case int64:
+			return stored != 0, nil
+		case uint64:
+			return stored != 0, nil
+		case int:
+			return stored != 0, nil
+		case json.Number:
+			number, err := stored.Int64()
+			if err != nil {
+				return nil, fmt.Errorf("cannot cast %s to bool", stored)
+			}
+			return number != 0, nil
+		case string:
+			return strconv.ParseBool(stored)
+		case []byte:
+			return strconv.ParseBool(string(stored))
+		}
+		return nil, fmt.Errorf("cannot cast %T to bool", value)
+	},
+	Serialize: func(value interface{}) (interface{}, error) {
+		if flag, ok := value.(bool); ok {
+			if flag {
+				return int64(1), nil
+			}
+			return int64(0), nil
+		}
+		return value, nil
+	},
+}
+
+// CastDecimal holds a DECIMAL column as a string so its digits are written back exactly. Combine it
+// with DecimalAsString, as DECIMAL values read as float64 have already lost precision
+var CastDecimal = Cast{
+	Hydrate:   decimalString,
+	Serialize: decimalString,
+}
+
+func decimalString(value interface{}) (interface{}, error) {
+	switch number := value.(type) {
+	case string:
+		return number, nil
+	case []byte:
+		return string(number), nil
+	case float64:
+		return strconv.FormatFloat(number, 'f', -1, 64), nil
+	case float32:
+		return strconv.FormatFloat(float64(number), 'f', -1, 32), nil
+	case int64:
+		return strconv.FormatInt(number, 10), nil
+	case int:
+		return strconv.Itoa(number), nil
+	case json.Number:
+		return number.String(), nil
+	}
+	return nil, fmt.Errorf("cannot cast %T to a decimal string", value)
+}
+
+// applies the table's Hydrate casts to the record's properties. NULLs aren't cast
+func (r *Record) hydrate() error {
+	for column, cast := range r.database.configs.Casts[r.table] {
+		value, ok := r.properties[column]
+		if !ok || value == nil || cast.Hydrate == nil {
+			continue
+		}
+		hydrated, err := cast.Hydrate(value)
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", r.table, column, err)
+		}
+		r.properties[column] = hydrated
+	}
+	return nil
+}
+
+// builds the placeholder and bound values for a property, cast and normalized as the table declares
+func (r *Record) bind(field string, value interface{}) (string, []interface{}, error) {
+	if cast, ok := r.database.configs.Casts[r.table][field]; ok && value != nil && cast.Serialize != nil {
+		var err error
+		value, err = cast.Serialize(value)
+		if err != nil {
+			return "", nil, fmt.Errorf("%s.%s: %w", r.table, field, err)
+		}
+	}
+	return r.database.bindPlaceholder(r.normalize(value))
+}
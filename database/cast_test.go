@@ -0,0 +1,123 @@
+package database
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCasts(t *testing.T) {
+	d := &Database{configs: &Configs{Casts: map[string]map[string]Cast{
+		"accounts": {"settings": CastJSON, "active": CastBool, "balance": CastDecimal},
+	}}}
+	record := d.MakeRecord(map[string]interface{}{
+		"settings": []byte(`{"theme": "dark"}`),
+		"active":   int64(1),
+		"balance":  "10.25",
+		"notes":    nil,
+	}, "accounts")
+	if err := record.hydrate(); err != nil {
+		t.Fatal(err)
+	}
+	settings, ok := record.properties["settings"].(map[string]interface{})
+	if !ok || settings["theme"] != "dark" {
+		t.Errorf("expected settings to be decoded, got %v", record.properties["settings"])
+	}
+	if record.properties["active"] != true {
+		t.Errorf("expected active to be true, got %v", record.properties["active"])
+	}
+	record.Set("settings", map[string]interface{}{"theme": "light"}).Set("active", false).Set("balance", 12.5)
+	expected := map[string]interface{}{"settings": `{"theme":"light"}`, "active": int64(0), "balance": "12.5"}
+	for field, want := range expected {
+		_, values, err := record.bind(field, record.properties[field])
+		if err != nil {
+			t.Error(err)
+		}
+		if len(values) != 1 || values[0] != want {
+			t.Errorf("expected %s to be written as %v, got %v", field, want, values)
+		}
+	}
+	record.Set("active", "maybe")
+	if err := record.hydrate(); err == nil {
+		t.Errorf("expected an invalid bool to fail to hydrate")
+	}
+}
+
+func TestCastsFromTypeOptions(t *testing.T) {
+	d := &Database{configs: &Configs{Casts: map[string]map[string]Cast{
+		"accounts": {"settings": CastJSON, "active": CastBool, "verified": CastBool},
+	}}}
+	record := d.MakeRecord(map[string]interface{}{
+		"settings": "",
+		"active":   1,
+		"verified": json.Number("0"),
+	}, "accounts")
+	if err := record.hydrate(); err != nil {
+		t.Fatal(err)
+	}
+	if record.properties["settings"] != nil {
+		t.Errorf("expected an empty JSON column to be nil, got %v", record.properties["settings"])
+	}
+	if record.properties["active"] != true || record.properties["verified"] != false {
+		t.Errorf("expected integer modes to cast to bools, got %v %v", record.properties["active"], record.properties["verified"])
+	}
+}
+
+func TestCastChangedInPlace(t *testing.T) {
+	d := &Database{configs: &Configs{Casts: map[string]map[string]Cast{"accounts": {"settings": CastJSON}}}}
+	record := d.MakeRecord(map[string]interface{}{"id": int64(1), "settings": []byte(`{"tags": ["a"], "theme": "dark"}`)}, "accounts")
+	if err := record.hydrate(); err != nil {
+		t.Fatal(err)
+	}
+	record.markClean()
+	settings := record.properties["settings"].(map[string]interface{})
+	settings["tags"].([]interface{})[0] = "b"
+	if !record.dirty("settings") {
+		t.Errorf("expected a nested change to the document to be dirty")
+	}
+	record.markClean()
+	settings = record.properties["settings"].(map[string]interface{})
+	settings["theme"] = "light"
+	if !record.dirty("settings") || record.dirty("id") {
+		t.Errorf("expected only the changed document to be dirty, got %v", record.Changes())
+	}
+}
+
+func TestCastUpdateInPlace(t *testing.T) {
+	defer recovery(t)
+	_, err := tdb.Exec("CREATE TABLE IF NOT EXISTS cast_accounts (id INT AUTO_INCREMENT PRIMARY KEY, settings JSON)", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	configs := getConfigs(false)
+	configs.Casts = map[string]map[string]Cast{"cast_accounts": {"settings": CastJSON}}
+	d, err := Make(configs)
+	if err != nil {
+		t.Error(err)
+	}
+	defer d.Close()
+	id, err := d.MakeRecord(map[string]interface{}{"settings": map[string]interface{}{"theme": "dark"}}, "cast_accounts").Create()
+	if err != nil {
+		t.Fatal(err)
+	}
+	record, err := d.FindRecord("cast_accounts", id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings, _ := record.Property("settings")
+	settings.(map[string]interface{})["theme"] = "light"
+	affected, err := record.Update("id")
+	if err != nil {
+		t.Error(err)
+	}
+	if affected != 1 {
+		t.Errorf("expected the changed document to be written, got %d rows", affected)
+	}
+	record, err = d.FindRecord("cast_accounts", id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings, _ = record.Property("settings")
+	if theme := settings.(map[string]interface{})["theme"]; theme != "light" {
+		t.Errorf("expected the theme to be light, got %v", theme)
+	}
+}
@@ -1,28 +1,56 @@
 package database
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
+	"net/url"
 	"os"
 	"reflect"
 	"strings"
+	"time"
 
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
 )
 
 // Database is a database connection
 type Database struct {
-	connection *sql.DB
-	configs    *Configs
-	Schemaless bool
+	pool      *connectionPool
+	configs   *Configs
+	types     *typeRegistry
+	async     *asyncQueue
+	lanes     map[Priority]chan struct{}
+	masks     MaskingProfile
+	enums     *enumCache
+	erasure   *erasureRegistry
+	columns   *columnCache
+	mirror    *mirror
+	hooks     *hookRegistry
+	relations *relationRegistry
+	models    *modelRegistry
+	server    *serverInfo
+	scopes    *scopeRegistry
+	applied   []string
+	ttl       *ttlRegistry
+	// TypeOptions sets the representation of numeric values in query results
+	TypeOptions TypeOptions
+	Schemaless  bool
 }
 
 type Record struct {
-	properties map[string]interface{}
-	database   *Database
-	table      string
+	properties    map[string]interface{}
+	database      *Database
+	table         string
+	uuidField     string
+	omitZero      bool
+	original      map[string]interface{}
+	versionColumn string
+	hooks         map[HookEvent][]Hook
+	ctx           context.Context
 }
 
 type Configs struct {
@@ -32,13 +60,119 @@ type Configs struct {
 	Port     string
 	Database string
 	Driver   string
+	// TimeZone is the session time_zone of every connection, as an offset ("+02:00") or a named
+	// zone loaded on the server ("Europe/London"); defaults to UTC
+	TimeZone string
+	// Location is the time zone used for DATE, DATETIME and TIMESTAMP values; defaults to TimeZone
+	Location *time.Location
+	// LegacyTemporalStrings returns DATE, DATETIME and TIMESTAMP columns as strings rather than time.Time,
+	// and TIME and YEAR columns as strings rather than time.Duration and int64
+	LegacyTemporalStrings bool
+	// NullAsNil returns NULL columns as nil rather than their type's zero value
+	NullAsNil bool
+	// LegacyBinaryStrings returns BINARY, VARBINARY and BLOB columns as strings rather than []byte
+	LegacyBinaryStrings bool
+	// DecodeJSON returns JSON columns decoded into maps, slices and scalars rather than strings
+	DecodeJSON bool
+	// DecimalAsString returns DECIMAL columns as exact strings (e.g. for decimal.NewFromString) rather than float64
+	DecimalAsString bool
+	// Lanes caps how many calls can run at once in each priority lane; lanes without a cap are unlimited
+	Lanes map[Priority]int
+	// MaxEstimatedRows refuses SELECTs without a LIMIT when EXPLAIN estimates more rows than this; zero disables the check
+	MaxEstimatedRows int64
+	// OnEstimateExceeded, when set, is called instead of refusing a query that exceeds MaxEstimatedRows
+	OnEstimateExceeded func(query string, estimate int64)
+	// TinyIntAsBool returns TINYINT columns (BOOL, BOOLEAN, TINYINT(1)) as bool. The driver does not
+	// report display widths, so this applies to every signed TINYINT column in a result
+	TinyIntAsBool bool
+	// MaxInListSize splits SELECTs with a longer IN (?, ...) list into several queries and merges their results.
+	// Queries using LIMIT, ORDER BY, GROUP BY, DISTINCT, aggregates, UNION, OR, subqueries or several IN lists
+	// are never split; zero disables splitting
+	MaxInListSize int
+	// SQLMode is set as the sql_mode of every new connection, e.g. SQLModeTraditional; empty keeps the server default
+	SQLMode string
+	// UUIDBinary stores UUID Record properties as BINARY(16) rather than CHAR(36)
+	UUIDBinary bool
+	// Normalizer is applied to every string query argument and Record property, e.g. norm.NFC.String
+	// from golang.org/x/text/unicode/norm
+	Normalizer Normalizer
+	// TableNormalizers are applied to string properties of Records for their table, before Normalizer
+	TableNormalizers map[string]Normalizer
+	// UUIDColumns are result columns converted to UUID, whether they are stored as CHAR(36) or BINARY(16)
+	UUIDColumns []string
+	// ValidateEnums checks ENUM and SET properties of Records against the permitted values before writing
+	ValidateEnums bool
+	// Timestamps are the tables whose Records get created_at and updated_at set by Create and updated_at
+	// refreshed by Update, where the table has those columns; "*" covers every table
+	Timestamps []string
+	// Clock is the time source for timestamps; defaults to time.Now
+	Clock func() time.Time
+	// Fillable lists the only columns Record writes may assign, per table
+	Fillable map[string][]string
+	// Guarded lists columns Record writes may not assign, per table
+	Guarded map[string][]string
+	// StrictAssignment makes Record writes fail on columns that aren't fillable rather than drop them
+	StrictAssignment bool
+	// Casts convert the values of Record properties as they are loaded and written, per table then column
+	Casts map[string]map[string]Cast
+	// Defaults are set on Records being created that don't have the column, per table then column.
+	// A func() interface{} default is called for each Record, e.g. to generate an identifier
+	Defaults map[string]map[string]interface{}
+	// WriteConflictRetries is how many times RunScript and DeleteInBatches rerun a transaction that fails
+	// on a write conflict, which TiDB's optimistic transactions report at commit, or on a deadlock
+	WriteConflictRetries int
+	// TiDB is the profile for TiDB servers, which IsTiDB detects: write conflicts are retried three times
+	// unless WriteConflictRetries is set
+	TiDB bool
+	// MaxOpenConnections caps the connections in the pool; zero leaves it unlimited
+	MaxOpenConnections int
+	// MaxIdleConnections is how many idle connections the pool keeps; zero keeps the driver's default
+	MaxIdleConnections int
+	// ConnectionMaxLifetime closes connections once they have been open this long; zero keeps them open
+	ConnectionMaxLifetime time.Duration
+}
+
+// Common sql_mode presets for Configs.SQLMode
+const (
+	SQLModeStrictAll   = "STRICT_ALL_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION"
+	SQLModeTraditional = "TRADITIONAL"
+	SQLModeANSI        = "ANSI"
+)
+
+// SQLModes combines sql_mode values for Configs.SQLMode
+func SQLModes(modes ...string) string {
+	return strings.Join(modes, ",")
+}
+
+// QueryOptions are per-call settings for QueryRawWithOptions and ExecWithOptions
+type QueryOptions struct {
+	// Context is the parent of the call's context, so a request's deadline or cancellation stops the call
+	Context context.Context
+	// Timeout cancels the call once it has elapsed; zero means no timeout
+	Timeout time.Duration
+	// Priority is the lane the call runs in; defaults to Interactive
+	Priority Priority
+	// NullPassthrough returns sql.NullString, sql.NullInt64, sql.NullFloat64, sql.NullTime and
+	// sql.NullBool values as they are, rather than their contents
+	NullPassthrough bool
 }
 
 // Make creates a new Database instance
 func Make(configs *Configs) (Database, error) {
 	database := Database{
-		connection: nil,
+		pool:       &connectionPool{},
 		configs:    configs,
+		types:      newTypeRegistry(configs),
+		lanes:      newLanes(configs),
+		enums:      newEnumCache(),
+		erasure:    &erasureRegistry{},
+		columns:    &columnCache{tables: make(map[string]map[string]bool)},
+		hooks:      &hookRegistry{tables: make(map[string]map[HookEvent][]Hook)},
+		relations:  &relationRegistry{tables: make(map[string]map[string]Relation)},
+		models:     &modelRegistry{types: make(map[reflect.Type]modelInfo)},
+		server:     &serverInfo{},
+		scopes:     &scopeRegistry{scopes: make(map[string]scope)},
+		ttl:        newTTLRegistry(),
 		Schemaless: false,
 	}
 
@@ -51,8 +185,19 @@ func Make(configs *Configs) (Database, error) {
 func MakeSchemaless(configs *Configs) (Database, error) {
 
 	database := Database{
-		connection: nil,
+		pool:       &connectionPool{},
 		configs:    configs,
+		types:      newTypeRegistry(configs),
+		lanes:      newLanes(configs),
+		enums:      newEnumCache(),
+		erasure:    &erasureRegistry{},
+		columns:    &columnCache{tables: make(map[string]map[string]bool)},
+		hooks:      &hookRegistry{tables: make(map[string]map[HookEvent][]Hook)},
+		relations:  &relationRegistry{tables: make(map[string]map[string]Relation)},
+		models:     &modelRegistry{types: make(map[reflect.Type]modelInfo)},
+		server:     &serverInfo{},
+		scopes:     &scopeRegistry{scopes: make(map[string]scope)},
+		ttl:        newTTLRegistry(),
 		Schemaless: true,
 	}
 
@@ -61,17 +206,51 @@ func MakeSchemaless(configs *Configs) (Database, error) {
 	return database, nil
 }
 
-// Close closes the database instance's connection
+// Close closes the database instance's connection, flushing any queued async writes and stopping
+// the TTL sweeper first
 func (database *Database) Close() {
-	database.connection.Close()
+	if database.async != nil {
+		database.async.stop()
+	}
+	database.StopSweeper()
+	database.connection().Close()
 }
 
 // Exec executes a query statement
 func (d *Database) Exec(query string, inserts []interface{}) (sql.Result, error) {
+	return d.ExecWithOptions(query, inserts, QueryOptions{})
+}
+
+// ExecWithOptions executes a query statement using per-call options
+func (d *Database) ExecWithOptions(query string, inserts []interface{}, options QueryOptions) (sql.Result, error) {
+	ctx, cancel := options.context()
+	defer cancel()
+	release, err := d.acquireLane(ctx, options.Priority)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	connection, done := d.acquire()
+	defer done()
+	return d.exec(ctx, connection, query, inserts)
+}
+
+// the ExecContext shared by the pool and transactions
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// executes a statement on the pool or a transaction, normalizing its arguments, tagging it with the
+// context's trace ID and mirroring it once it succeeds
+func (d *Database) exec(ctx context.Context, connection execer, query string, inserts []interface{}) (sql.Result, error) {
 	if inserts != nil {
-		return d.connection.Exec(query, inserts[:]...)
+		inserts = d.normalizeArgs(inserts)
+	}
+	result, err := connection.ExecContext(ctx, traceQuery(ctx, query), inserts[:]...)
+	if err == nil && d.mirror != nil {
+		d.mirror.write(ctx, query, inserts, result)
 	}
-	return d.connection.Exec(query)
+	return result, traceError(ctx, err)
 }
 
 // Name returns the name of the database instance
@@ -105,6 +284,15 @@ func (d *Database) hasAllConfigs() bool {
 
 func (d *Database) connect() {
 	// connect to database
+	connection, err := d.open(d.connectionString())
+	d.pool.swap(connection, d.configs)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// builds the DSN from the configs
+func (d *Database) connectionString() string {
 	connectionString := fmt.Sprintf("%s:%s@tcp(%s:%s)/",
 		d.configs.Username,
 		d.configs.Password,
@@ -114,19 +302,86 @@ func (d *Database) connect() {
 	if !d.Schemaless {
 		connectionString += d.configs.Database
 	}
-	connection, err := sql.Open(d.configs.Driver, connectionString)
-	d.connection = connection
+	return connectionString + d.connectionParams()
+}
+
+// opens the connection pool, setting the location on the driver directly as the DSN only takes named zones
+func (d *Database) open(connectionString string) (*sql.DB, error) {
+	location := d.location()
+	if d.configs.Driver != "mysql" || location == nil || d.configs.LegacyTemporalStrings {
+		connection, err := sql.Open(d.configs.Driver, connectionString)
+		if err != nil {
+			return nil, err
+		}
+		d.sizePool(connection)
+		return connection, nil
+	}
+	driverConfigs, err := mysql.ParseDSN(connectionString)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
-	d.setUTC()
+	driverConfigs.Loc = location
+	connector, err := mysql.NewConnector(driverConfigs)
+	if err != nil {
+		return nil, err
+	}
+	connection := sql.OpenDB(connector)
+	d.sizePool(connection)
+	return connection, nil
 }
 
-func (d *Database) setUTC() {
-	_, err := d.Exec("SET @@session.time_zone='+00:00';", []interface{}{})
+// applies the configured pool limits, leaving the driver's defaults for those not set
+func (d *Database) sizePool(connection *sql.DB) {
+	if d.configs.MaxOpenConnections > 0 {
+		connection.SetMaxOpenConns(d.configs.MaxOpenConnections)
+	}
+	if d.configs.MaxIdleConnections > 0 {
+		connection.SetMaxIdleConns(d.configs.MaxIdleConnections)
+	}
+	if d.configs.ConnectionMaxLifetime > 0 {
+		connection.SetConnMaxLifetime(d.configs.ConnectionMaxLifetime)
+	}
+}
+
+// builds the DSN parameters from the configs
+func (d *Database) connectionParams() string {
+	params := url.Values{}
+	params.Set("time_zone", "'"+strings.ReplaceAll(d.timeZone(), "'", "")+"'")
+	if !d.configs.LegacyTemporalStrings {
+		params.Set("parseTime", "true")
+	}
+	if len(d.configs.SQLMode) > 0 {
+		params.Set("sql_mode", "'"+strings.ReplaceAll(d.configs.SQLMode, "'", "")+"'")
+	}
+	return "?" + params.Encode()
+}
+
+// the session time zone for every connection, UTC unless configured
+func (d *Database) timeZone() string {
+	if len(d.configs.TimeZone) > 0 {
+		return d.configs.TimeZone
+	}
+	return "+00:00"
+}
+
+// the location for time.Time values, matching the session time zone unless configured
+func (d *Database) location() *time.Location {
+	if d.configs.Location != nil {
+		return d.configs.Location
+	}
+	if len(d.configs.TimeZone) < 1 {
+		return nil
+	}
+	if offset, err := time.Parse("-07:00", d.configs.TimeZone); err == nil {
+		_, seconds := offset.Zone()
+		return time.FixedZone(d.configs.TimeZone, seconds)
+	}
+	location, err := time.LoadLocation(d.configs.TimeZone)
 	if err != nil {
 		log.Println(err)
+		return nil
 	}
+	return location
 }
 
 // SetSchema sets a DB instance to having a schema
@@ -138,14 +393,62 @@ func (d *Database) SetSchema(schemaName string) {
 
 // QueryRaw runs a raw select query against the database
 func (d *Database) QueryRaw(query string, escaped []interface{}) ([]map[string]interface{}, error) {
-	rowResult, err := d.getRowResult(query, escaped)
+	return d.QueryRawWithOptions(query, escaped, QueryOptions{})
+}
+
+// QueryRawWithOptions runs a raw select query against the database using per-call options
+func (d *Database) QueryRawWithOptions(query string, escaped []interface{}, options QueryOptions) ([]map[string]interface{}, error) {
+	escaped = d.normalizeArgs(escaped)
+	ctx, cancel := options.context()
+	defer cancel()
+	release, err := d.acquireLane(ctx, options.Priority)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	err = d.checkEstimate(ctx, query, escaped)
 	if err != nil {
 		return nil, err
 	}
-	return parseRowResults(rowResult)
+	chunks := d.splitInList(query, escaped)
+	if chunks == nil {
+		return d.queryContext(ctx, query, escaped, options)
+	}
+	result := make([]map[string]interface{}, 0)
+	for _, chunk := range chunks {
+		rows, err := d.queryContext(ctx, chunk.query, chunk.escaped, options)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, rows...)
+	}
+	return result, nil
+}
+
+func (d *Database) queryContext(ctx context.Context, query string, escaped []interface{}, options QueryOptions) ([]map[string]interface{}, error) {
+	connection, release := d.acquire()
+	defer release()
+	rowResult, err := d.getRowResult(ctx, connection, traceQuery(ctx, query), escaped)
+	if err != nil {
+		return nil, traceError(ctx, err)
+	}
+	rows, err := d.parseRowResults(rowResult, options)
+	return rows, traceError(ctx, err)
+}
+
+// builds the context for a call, applying the timeout if there is one
+func (o QueryOptions) context() (context.Context, context.CancelFunc) {
+	parent := o.Context
+	if parent == nil {
+		parent = context.Background()
+	}
+	if o.Timeout > 0 {
+		return context.WithTimeout(parent, o.Timeout)
+	}
+	return context.WithCancel(parent)
 }
 
-func parseRowResults(rowResult *sql.Rows) ([]map[string]interface{}, error) {
+func (d *Database) parseRowResults(rowResult *sql.Rows, options QueryOptions) ([]map[string]interface{}, error) {
 	cols, err := rowResult.Columns()
 	if err != nil {
 		return nil, err
@@ -155,7 +458,7 @@ func parseRowResults(rowResult *sql.Rows) ([]map[string]interface{}, error) {
 		return nil, err
 	}
 
-	return rowResultWalk(rowResult, cols, typeMapping)
+	return d.rowResultWalk(rowResult, cols, typeMapping, options)
 }
 
 func getTypeMapping(rowResult *sql.Rows) (map[string]string, error) {
@@ -167,8 +470,8 @@ func getTypeMapping(rowResult *sql.Rows) (map[string]string, error) {
 	return typeMapping, nil
 }
 
-func getResultantRow(cols []string, typeMapping map[string]string, rowResult *sql.Rows) (map[string]interface{}, error) {
-	row := makeRow(typeMapping, cols)
+func (d *Database) getResultantRow(cols []string, typeMapping map[string]string, rowResult *sql.Rows, options QueryOptions) (map[string]interface{}, error) {
+	row := d.makeRow(typeMapping, cols)
 	err := rowResult.Scan(row...)
 	if err != nil {
 		return nil, err
@@ -176,18 +479,29 @@ func getResultantRow(cols []string, typeMapping map[string]string, rowResult *sq
 	resultRow := make(map[string]interface{})
 	var count = 0
 	for _, v := range row {
-		rowValue := getRowValue(v)
+		rowValue := d.TypeOptions.convert(d.getRowValue(v))
+		if options.NullPassthrough {
+			if nullVal, ok := nullPassthrough(v); ok {
+				rowValue = nullVal
+			}
+		}
+		if d.isUUIDColumn(cols[count]) {
+			rowValue = toUUID(rowValue, contains(binaryTypes, typeMapping[cols[count]]))
+		}
+		if mask, ok := d.masks[cols[count]]; ok && rowValue != nil && rowValue != "" {
+			rowValue = mask(rowValue)
+		}
 		resultRow[cols[count]] = rowValue
 		count++
 	}
 	return resultRow, nil
 }
 
-func rowResultWalk(rowResult *sql.Rows, cols []string, typeMapping map[string]string) ([]map[string]interface{}, error) {
+func (d *Database) rowResultWalk(rowResult *sql.Rows, cols []string, typeMapping map[string]string, options QueryOptions) ([]map[string]interface{}, error) {
 	defer rowResult.Close()
 	result := make([]map[string]interface{}, 0)
 	for rowResult.Next() {
-		resultRow, err := getResultantRow(cols, typeMapping, rowResult)
+		resultRow, err := d.getResultantRow(cols, typeMapping, rowResult, options)
 		if err != nil {
 			return nil, err
 		}
@@ -200,8 +514,8 @@ func rowResultWalk(rowResult *sql.Rows, cols []string, typeMapping map[string]st
 	return result, nil
 }
 
-func (d *Database) getRowResult(query string, escaped []interface{}) (*sql.Rows, error) {
-	rows, err := d.getRows(query, escaped)
+func (d *Database) getRowResult(ctx context.Context, connection *sql.DB, query string, escaped []interface{}) (*sql.Rows, error) {
+	rows, err := d.getRows(ctx, connection, query, escaped)
 	if err != nil {
 		return nil, err
 	}
@@ -215,6 +529,9 @@ func (d *Database) getRowResult(query string, escaped []interface{}) (*sql.Rows,
 	return rowResult, nil
 }
 
+// ErrNoResult is returned when a query expected to find a row, such as Row or a Record's Load, finds none
+var ErrNoResult = errors.New("no result")
+
 // Row gets a row from the query
 func (d *Database) Row(query string, id int64) (map[string]interface{}, error) {
 	rows, err := d.QueryRaw(query, []interface{}{
@@ -224,7 +541,7 @@ func (d *Database) Row(query string, id int64) (map[string]interface{}, error) {
 		return nil, err
 	}
 	if len(rows) < 1 {
-		return nil, errors.New("no result")
+		return nil, ErrNoResult
 	}
 	return rows[0], nil
 }
@@ -238,21 +555,46 @@ func (d *Database) RowByStringField(query string, field string) (map[string]inte
 		return nil, err
 	}
 	if len(rows) < 1 {
-		return nil, errors.New("no result")
+		return nil, ErrNoResult
 	}
 	return rows[0], nil
 }
 
-func (d *Database) getRows(query string, escaped []interface{}) (interface{}, error) {
+// Exists reports whether the query returns at least one row
+func (d *Database) Exists(query string, escaped []interface{}) (bool, error) {
+	return d.ExistsWithOptions(query, escaped, QueryOptions{})
+}
+
+// ExistsWithOptions reports whether the query returns at least one row using per-call options
+func (d *Database) ExistsWithOptions(query string, escaped []interface{}, options QueryOptions) (bool, error) {
+	query = strings.TrimRight(strings.TrimSpace(query), ";")
+	ctx, cancel := options.context()
+	defer cancel()
+	release, err := d.acquireLane(ctx, options.Priority)
+	if err != nil {
+		return false, err
+	}
+	defer release()
+	connection, done := d.acquire()
+	defer done()
+	var exists bool
+	err = connection.QueryRowContext(ctx, traceQuery(ctx, fmt.Sprintf("SELECT EXISTS(%s)", query)), d.normalizeArgs(escaped)...).Scan(&exists)
+	if err != nil {
+		return false, traceError(ctx, err)
+	}
+	return exists, nil
+}
+
+func (d *Database) getRows(ctx context.Context, connection *sql.DB, query string, escaped []interface{}) (interface{}, error) {
 	if escaped != nil {
-		rows, err := d.connection.Query(query, escaped[:]...)
+		rows, err := connection.QueryContext(ctx, query, escaped[:]...)
 		if err != nil {
 			return nil, err
 		}
 
 		return rows, nil
 	} else {
-		rows, err := d.connection.Query(query)
+		rows, err := connection.QueryContext(ctx, query)
 		if err != nil {
 			return nil, err
 		}
@@ -273,24 +615,33 @@ func mapColTypes(colTypes []*sql.ColumnType) map[string]string {
 }
 
 // gets the value of a row
-func getRowValue(row interface{}) interface{} {
+func (d *Database) getRowValue(row interface{}) interface{} {
 	rowValueOf := reflect.ValueOf(row)
 	if rowValueOf.Kind() == reflect.Interface || rowValueOf.Kind() == reflect.Ptr {
 		rowValue := rowValueOf.Elem()
 		if rowValue.CanInterface() {
 			rowValue := rowValue.Interface()
 
+			if resultVal, ok := (rowValue).(resultValue); ok {
+				value, valid := resultVal.result()
+				return d.nullable(valid, value)
+			}
+
 			// TODO add all sql.* types
 			if stringVal, ok := (rowValue).(sql.NullString); ok {
-				return stringVal.String
+				return d.nullable(stringVal.Valid, stringVal.String)
 			}
 
 			if intVal, ok := (rowValue).(sql.NullInt64); ok {
-				return intVal.Int64
+				return d.nullable(intVal.Valid, intVal.Int64)
 			}
 
 			if floatVal, ok := (rowValue).(sql.NullFloat64); ok {
-				return floatVal.Float64
+				return d.nullable(floatVal.Valid, floatVal.Float64)
+			}
+
+			if timeVal, ok := (rowValue).(sql.NullTime); ok {
+				return d.nullable(timeVal.Valid, timeVal.Time)
 			}
 
 			return rowValue
@@ -300,114 +651,30 @@ func getRowValue(row interface{}) interface{} {
 	return row
 }
 
-// makes a new row based on the database column type returned
-func makeRow(typeMapping map[string]string, cols []string) []interface{} {
-	row := make([]interface{}, 0)
-	for _, v := range cols {
-		switch typeMapping[v] {
-		case "INT":
-			var newCol sql.NullInt64
-			row = append(row, &newCol)
-		case "BIT":
-			var newCol sql.NullInt64
-			row = append(row, &newCol)
-		case "TINYINT":
-			var newCol sql.NullInt64
-			row = append(row, &newCol)
-		case "BOOL":
-			var newCol sql.NullInt64
-			row = append(row, &newCol)
-		case "BOOLEAN":
-			var newCol sql.NullInt64
-			row = append(row, &newCol)
-		case "SMALLINT":
-			var newCol sql.NullInt64
-			row = append(row, &newCol)
-		case "MEDIUMINT":
-			var newCol sql.NullInt64
-			row = append(row, &newCol)
-		case "INTEGER":
-			var newCol sql.NullInt64
-			row = append(row, &newCol)
-		case "BIGINT":
-			var newCol sql.NullInt64
-			row = append(row, &newCol)
-		case "FLOAT":
-			var newCol sql.NullFloat64
-			row = append(row, &newCol)
-		case "DOUBLE":
-			var newCol sql.NullFloat64
-			row = append(row, &newCol)
-		case "DECIMAL":
-			var newCol sql.NullFloat64
-			row = append(row, &newCol)
-		case "DEC":
-			var newCol sql.NullFloat64
-			row = append(row, &newCol)
-		case "CHAR":
-			var newCol sql.NullString
-			row = append(row, &newCol)
-		case "VARCHAR":
-			var newCol sql.NullString
-			row = append(row, &newCol)
-		case "BINARY":
-			var newCol sql.NullString
-			row = append(row, &newCol)
-		case "VARBINARY":
-			var newCol sql.NullString
-			row = append(row, &newCol)
-		case "TINYBLOB":
-			var newCol sql.NullString
-			row = append(row, &newCol)
-		case "TINYTEXT":
-			var newCol sql.NullString
-			row = append(row, &newCol)
-		case "TEXT":
-			var newCol sql.NullString
-			row = append(row, &newCol)
-		case "BLOB":
-			var newCol sql.NullString
-			row = append(row, &newCol)
-		case "MEDIUMTEXT":
-			var newCol sql.NullString
-			row = append(row, &newCol)
-		case "MEDIUMBLOB":
-			var newCol sql.NullString
-			row = append(row, &newCol)
-		case "LONGTEXT":
-			var newCol sql.NullString
-			row = append(row, &newCol)
-		case "LONGBLOB":
-			var newCol sql.NullString
-			row = append(row, &newCol)
-		case "ENUM":
-			var newCol sql.NullString
-			row = append(row, &newCol)
-		case "SET":
-			var newCol sql.NullString
-			row = append(row, &newCol)
-		case "DATE":
-			var newCol sql.NullString
-			row = append(row, &newCol)
-		case "DATETIME":
-			var newCol sql.NullString
-			row = append(row, &newCol)
-		case "TIMESTAMP":
-			var newCol sql.NullString
-			row = append(row, &newCol)
-		case "TIME":
-			var newCol sql.NullString
-			row = append(row, &newCol)
-		case "YEAR":
-			var newCol sql.NullString
-			row = append(row, &newCol)
-		default:
-			var newCol sql.NullString
-			row = append(row, &newCol)
-		}
+// returns sql.Null* scan destinations as they are, so callers can check Valid
+func nullPassthrough(row interface{}) (interface{}, bool) {
+	switch nullVal := row.(type) {
+	case *sql.NullString:
+		return *nullVal, true
+	case *sql.NullInt64:
+		return *nullVal, true
+	case *sql.NullFloat64:
+		return *nullVal, true
+	case *sql.NullTime:
+		return *nullVal, true
+	case *nullBool:
+		return sql.NullBool{Bool: nullVal.Bool, Valid: nullVal.Valid}, true
+	default:
+		return nil, false
 	}
+}
 
-	return row
+// returns nil for NULL values when the configs ask for it
+func (d *Database) nullable(valid bool, value interface{}) interface{} {
+	if !valid && d.configs.NullAsNil {
+		return nil
+	}
+	return value
 }
 
 func (d *Database) supplementConfigs() {
@@ -482,65 +749,271 @@ func (d *Database) MakeRecord(properties map[string]interface{}, table string) *
 	return record
 }
 
-// Create creates a new record
+// Result reports the outcome of a write
+type Result struct {
+	LastInsertID int64
+	RowsAffected int64
+}
+
+// Create creates a new record, returning its id
 func (r *Record) Create() (int64, error) {
+	result, err := r.CreateResult()
+	return result.LastInsertID, err
+}
+
+// CreateResult creates a new record, returning both its id and the number of rows inserted
+func (r *Record) CreateResult() (Result, error) {
+	err := r.beforeCreate()
+	if err != nil {
+		return Result{}, err
+	}
+	insertStatement, inserts, err := r.insertStatement()
+	if err != nil {
+		return Result{}, err
+	}
+	insert, err := r.database.ExecWithOptions(insertStatement, inserts, r.options())
 
-	insertStatement := "INSERT INTO `" + r.database.Name() + "`.`" + r.table + "` (`@fields`) VALUES (@values)"
+	// handle any error with the insert
+	if err != nil {
+		return Result{}, err
+	}
+	r.markClean()
+	id, err := insert.LastInsertId()
+	if err != nil {
+		return Result{}, err
+	}
+	affected, err := insert.RowsAffected()
+	if err != nil {
+		return Result{}, err
+	}
+	r.sampleWrite(defaultKey, id)
+	return Result{LastInsertID: id, RowsAffected: affected}, r.runHooks(AfterCreate)
+}
+
+// guards, stamps and validates the record's properties, and runs the BeforeCreate hooks
+func (r *Record) beforeCreate() error {
+	err := r.guard()
+	if err != nil {
+		return err
+	}
+	r.applyDefaults()
+	err = r.generateUUID()
+	if err != nil {
+		return err
+	}
+	err = r.touch(true)
+	if err != nil {
+		return err
+	}
+	if len(r.versionColumn) > 0 && r.properties[r.versionColumn] == nil {
+		r.Set(r.versionColumn, int64(1))
+	}
+	err = r.runHooks(BeforeCreate)
+	if err != nil {
+		return err
+	}
+	if r.database.configs.ValidateEnums {
+		return r.ValidateEnums()
+	}
+	return nil
+}
+
+// builds the INSERT statement for the record's properties
+func (r *Record) insertStatement() (string, []interface{}, error) {
+	if err := r.validateIdentifiers(r.fields()); err != nil {
+		return "", nil, err
+	}
+	insertStatement := "INSERT INTO " + quoteIdentifier(r.database.Name()) + "." + quoteIdentifier(r.table) + " (@fields) VALUES (@values)"
 
 	var inserts []interface{}
 	var fields []string
 	var valuesEscapes []string
 
-	for field, value := range r.properties {
-		fields = append(fields, field)
-		valuesEscapes = append(valuesEscapes, "?")
-		inserts = append(inserts, value)
+	for _, field := range r.fields() {
+		value := r.properties[field]
+		if r.omitted(value) {
+			continue
+		}
+		placeholder, values, err := r.bind(field, value)
+		if err != nil {
+			return "", nil, err
+		}
+		fields = append(fields, quoteIdentifier(field))
+		valuesEscapes = append(valuesEscapes, placeholder)
+		inserts = append(inserts, values...)
 	}
 
-	insertStatement = strings.Replace(insertStatement, "@fields", strings.Join(fields, "`, `"), 1)
+	insertStatement = strings.Replace(insertStatement, "@fields", strings.Join(fields, ", "), 1)
 	insertStatement = strings.Replace(insertStatement, "@values", strings.Join(valuesEscapes, ", "), 1)
-	insert, err := r.database.Exec(insertStatement, inserts)
+	return insertStatement, inserts, nil
+}
 
-	// handle any error with the insert
-	if err != nil {
-		return 0, err
+// OmitZero makes Create and Update skip properties set to their type's zero value or a nil pointer,
+// so partially populated input doesn't overwrite existing data
+func (r *Record) OmitZero() *Record {
+	r.omitZero = true
+	return r
+}
+
+func (r *Record) omitted(value interface{}) bool {
+	if !r.omitZero {
+		return false
+	}
+	if value == nil {
+		return true
 	}
-	return insert.LastInsertId()
+	return reflect.ValueOf(value).IsZero()
 }
 
-// Update updates an existing record
+// Update updates an existing record by its id column, or the given key column, returning the number
+// of rows changed
 func (r *Record) Update(id string) (int64, error) {
+	if len(id) < 1 {
+		id = defaultKey
+	}
+	return r.UpdateBy([]string{id})
+}
 
-	updateStatement := "UPDATE `" + r.database.Name() + "`.`" + r.table + "` SET "
+// UpdateBy updates the row matching the record on every one of the given key columns, such as the
+// columns of a composite primary key, returning the number of rows changed
+func (r *Record) UpdateBy(keys []string) (int64, error) {
+	if len(keys) < 1 {
+		keys = []string{defaultKey}
+	}
+	if err := r.guard(keys...); err != nil {
+		return 0, err
+	}
+	if err := r.runHooks(BeforeUpdate); err != nil {
+		return 0, err
+	}
+	if r.database.configs.ValidateEnums {
+		if err := r.ValidateEnums(); err != nil {
+			return 0, err
+		}
+	}
+	if r.original != nil && len(r.Changes()) < 1 {
+		return 0, nil
+	}
+	if err := r.touch(false); err != nil {
+		return 0, err
+	}
+	where, keyValues, err := r.keyCondition(keys, "update")
+	if err != nil {
+		return 0, err
+	}
+	if err := r.validateIdentifiers(r.fields()); err != nil {
+		return 0, err
+	}
+
+	updateStatement := "UPDATE " + quoteIdentifier(r.database.Name()) + "." + quoteIdentifier(r.table) + " SET "
 
 	var inserts []interface{}
-	where := " WHERE "
 
-	// empty string for ID property uses the default "id"
-	if len(id) < 1 {
-		id = "id"
+	for _, field := range r.fields() {
+		value := r.properties[field]
+		if !contains(keys, field) && field != r.versionColumn && !r.omitted(value) && r.dirty(field) {
+			placeholder, values, err := r.bind(field, value)
+			if err != nil {
+				return 0, err
+			}
+			updateStatement += quoteIdentifier(field) + " = " + placeholder + ", "
+			inserts = append(inserts, values...)
+		}
 	}
 
-	for field, value := range r.properties {
-		if field == id {
-			where += id + " = ?;"
-		} else {
-			updateStatement += field + " = ?, "
-			inserts = append(inserts, value)
-		}
+	if len(inserts) < 1 {
+		return 0, errors.New("no properties to update")
 	}
 
-	inserts = append(inserts, r.properties[id])
+	inserts = append(inserts, keyValues...)
 
-	updateStatement = strings.TrimRight(updateStatement, ", ") + where
+	updateStatement = strings.TrimRight(updateStatement, ", ")
 
-	insert, err := r.database.Exec(updateStatement, inserts)
+	var version int64
+	if len(r.versionColumn) > 0 {
+		version, err = r.GetInt64(r.versionColumn)
+		if err != nil {
+			return 0, err
+		}
+		column := quoteIdentifier(r.versionColumn)
+		updateStatement += ", " + column + " = " + column + " + 1"
+		where += " AND " + column + " = ?"
+		inserts = append(inserts, version)
+	}
+
+	insert, err := r.database.ExecWithOptions(updateStatement+" WHERE "+where, inserts, r.options())
 
 	// handle any error with the insert
 	if err != nil {
 		return 0, err
 	}
-	return insert.LastInsertId()
+	affected, err := insert.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if len(r.versionColumn) > 0 {
+		if affected < 1 {
+			return 0, ErrStaleRecord
+		}
+		r.properties[r.versionColumn] = version + 1
+	}
+	r.markClean()
+	if len(keys) == 1 {
+		r.sampleWrite(keys[0], 0)
+	}
+	return affected, r.runHooks(AfterUpdate)
+}
+
+// sqlExpression is implemented by Record property values that are written through a SQL expression
+type sqlExpression interface {
+	expression() (string, []interface{}, error)
+}
+
+// builds the placeholder and bound values for a Record property
+func (d *Database) bindPlaceholder(value interface{}) (string, []interface{}, error) {
+	if expr, ok := value.(sqlExpression); ok {
+		return expr.expression()
+	}
+	value, err := d.bindValue(value)
+	if err != nil {
+		return "", nil, err
+	}
+	return "?", []interface{}{value}, nil
+}
+
+// converts a Record property into a value the driver can bind, marshalling maps and slices to JSON
+func (d *Database) bindValue(value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	if uuid, ok := value.(UUID); ok {
+		return d.bindUUID(uuid), nil
+	}
+	if _, ok := value.(driver.Valuer); ok {
+		return value, nil
+	}
+	if _, ok := value.([]byte); ok {
+		return value, nil
+	}
+	if duration, ok := value.(time.Duration); ok {
+		return FormatTimeValue(duration), nil
+	}
+	if boolVal, ok := value.(bool); ok {
+		if boolVal {
+			return int64(1), nil
+		}
+		return int64(0), nil
+	}
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array:
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		return string(encoded), nil
+	default:
+		return value, nil
+	}
 }
 
 func (d *Database) CheckHasTable(table string) (bool, error) {
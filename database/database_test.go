@@ -1,7 +1,9 @@
 package database
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"math"
@@ -167,6 +169,36 @@ func TestQueryRaw(t *testing.T) {
 	}
 }
 
+func TestQueryRawWithOptions(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	rows, err := tdb.QueryRawWithOptions("select sku from widgets where id = ?", []interface{}{1}, QueryOptions{
+		Timeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	if len(rows) != 1 {
+		t.Errorf("expected one row, got %d", len(rows))
+	}
+	_, err = tdb.QueryRawWithOptions("select sleep(2)", nil, QueryOptions{
+		Timeout: 100 * time.Millisecond,
+	})
+	if err == nil {
+		t.Errorf("expected the query to time out")
+	}
+}
+
+func TestExecWithOptions(t *testing.T) {
+	defer recovery(t)
+	_, err := tdb.ExecWithOptions("do sleep(2)", nil, QueryOptions{
+		Timeout: 100 * time.Millisecond,
+	})
+	if err == nil {
+		t.Errorf("expected the statement to time out")
+	}
+}
+
 func TestRow(t *testing.T) {
 	defer recovery(t)
 	createWidgetsTable(t)
@@ -231,6 +263,177 @@ func TestRowByStringField(t *testing.T) {
 	}
 }
 
+func TestTemporalColumns(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	row, err := tdb.Row("select created_at, date(created_at) as created_on from widgets where id = ?", 1)
+	if err != nil {
+		t.Error(err)
+	}
+	for _, col := range []string{"created_at", "created_on"} {
+		createdAt, ok := row[col].(time.Time)
+		if !ok {
+			t.Errorf("expected %s to be a time.Time, got %T", col, row[col])
+			continue
+		}
+		if createdAt.IsZero() {
+			t.Errorf("expected %s to be set", col)
+		}
+	}
+}
+
+func TestLegacyTemporalStrings(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	configs := getConfigs(false)
+	configs.LegacyTemporalStrings = true
+	d, err := Make(configs)
+	if err != nil {
+		t.Error(err)
+	}
+	defer d.Close()
+	row, err := d.Row("select created_at from widgets where id = ?", 1)
+	if err != nil {
+		t.Error(err)
+	}
+	if _, ok := row["created_at"].(string); !ok {
+		t.Errorf("expected created_at to be a string, got %T", row["created_at"])
+	}
+}
+
+func TestNullAsNil(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	configs := getConfigs(false)
+	configs.NullAsNil = true
+	d, err := Make(configs)
+	if err != nil {
+		t.Error(err)
+	}
+	defer d.Close()
+	row, err := d.Row("select null as description, cast(null as signed) as weight, 0 as zero from widgets where id = ?", 1)
+	if err != nil {
+		t.Error(err)
+	}
+	if row["description"] != nil {
+		t.Errorf("expected description to be nil, got %v", row["description"])
+	}
+	if row["weight"] != nil {
+		t.Errorf("expected weight to be nil, got %v", row["weight"])
+	}
+	if row["zero"] != int64(0) {
+		t.Errorf("expected zero to be 0, got %v", row["zero"])
+	}
+	row, err = tdb.Row("select null as description from widgets where id = ?", 1)
+	if err != nil {
+		t.Error(err)
+	}
+	if row["description"] != "" {
+		t.Errorf("expected description to be an empty string, got %v", row["description"])
+	}
+}
+
+func TestTimeZone(t *testing.T) {
+	defer recovery(t)
+	configs := getConfigs(false)
+	configs.TimeZone = "+02:00"
+	d, err := Make(configs)
+	if err != nil {
+		t.Error(err)
+	}
+	defer d.Close()
+	rows, err := d.QueryRaw("select @@session.time_zone as zone, timestamp('2023-01-01 12:00:00') as noon", nil)
+	if err != nil || len(rows) != 1 {
+		t.Fatalf("expected one row, got %v", err)
+	}
+	if rows[0]["zone"] != "+02:00" {
+		t.Errorf("expected the session time zone to be +02:00, got %v", rows[0]["zone"])
+	}
+	noon, ok := rows[0]["noon"].(time.Time)
+	if !ok {
+		t.Fatalf("expected noon to be a time.Time, got %T", rows[0]["noon"])
+	}
+	if noon.UTC().Hour() != 10 {
+		t.Errorf("expected noon at +02:00 to be 10:00 UTC, got %s", noon.UTC())
+	}
+	rows, err = tdb.QueryRaw("select @@session.time_zone as zone", nil)
+	if err != nil || len(rows) != 1 {
+		t.Fatalf("expected one row, got %v", err)
+	}
+	if rows[0]["zone"] != "+00:00" {
+		t.Errorf("expected the default session time zone to be +00:00, got %v", rows[0]["zone"])
+	}
+}
+
+func TestSQLMode(t *testing.T) {
+	defer recovery(t)
+	configs := getConfigs(false)
+	configs.SQLMode = SQLModes(SQLModeTraditional, "NO_AUTO_VALUE_ON_ZERO")
+	d, err := Make(configs)
+	if err != nil {
+		t.Error(err)
+	}
+	defer d.Close()
+	rows, err := d.QueryRaw("select @@session.sql_mode as mode", nil)
+	if err != nil || len(rows) != 1 {
+		t.Fatalf("expected one row, got %v", err)
+	}
+	mode, _ := rows[0]["mode"].(string)
+	for _, expected := range []string{"STRICT_TRANS_TABLES", "STRICT_ALL_TABLES", "NO_AUTO_VALUE_ON_ZERO"} {
+		if !strings.Contains(mode, expected) {
+			t.Errorf("expected sql_mode to contain %s, got %s", expected, mode)
+		}
+	}
+}
+
+func TestNullPassthrough(t *testing.T) {
+	defer recovery(t)
+	rows, err := tdb.QueryRawWithOptions("select null as description, 1.5 as weight", nil, QueryOptions{
+		NullPassthrough: true,
+	})
+	if err != nil || len(rows) != 1 {
+		t.Fatalf("expected one row, got %v", err)
+	}
+	description, ok := rows[0]["description"].(sql.NullString)
+	if !ok || description.Valid {
+		t.Errorf("expected description to be an invalid sql.NullString, got %#v", rows[0]["description"])
+	}
+	weight, ok := rows[0]["weight"].(sql.NullFloat64)
+	if !ok || !weight.Valid || weight.Float64 != 1.5 {
+		t.Errorf("expected weight to be a valid sql.NullFloat64, got %#v", rows[0]["weight"])
+	}
+}
+
+func TestExists(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	exists, err := tdb.Exists("select id from widgets where sku = ?", []interface{}{"WIDG1"})
+	if err != nil {
+		t.Error(err)
+	}
+	if !exists {
+		t.Errorf("expected widget 'WIDG1' to exist")
+	}
+	exists, err = tdb.Exists("select id from widgets where sku = ?;", []interface{}{"WIDG-MISSING"})
+	if err != nil {
+		t.Error(err)
+	}
+	if exists {
+		t.Errorf("expected widget 'WIDG-MISSING' not to exist")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = tdb.ExistsWithOptions("select id from widgets where sku = ?", []interface{}{"WIDG1"}, QueryOptions{Context: ctx})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected a cancelled context to stop the check, got %v", err)
+	}
+	ctx = WithTraceID(context.Background(), "exists-trace")
+	_, err = tdb.ExistsWithOptions("select no_such_column from widgets", nil, QueryOptions{Context: ctx})
+	if err == nil || !strings.Contains(err.Error(), "trace_id exists-trace") {
+		t.Errorf("expected the error to carry the trace ID, got %v", err)
+	}
+}
+
 func TestCreateRecord(t *testing.T) {
 	defer recovery(t)
 	createWidgetsTable(t)
@@ -248,16 +451,56 @@ func TestCreateRecord(t *testing.T) {
 func TestUpdateRecord(t *testing.T) {
 	defer recovery(t)
 	createWidgetsTable(t)
-	_, err := tdb.MakeRecord(map[string]interface{}{
+	affected, err := tdb.MakeRecord(map[string]interface{}{
 		"weight": 110.9,
 		"sku":    "WIDG4",
 	}, "widgets").Update("sku")
 	if err != nil {
 		t.Error(err)
 	}
+	if affected != 1 {
+		t.Errorf("expected one row to be updated, got %d", affected)
+	}
 	checkWidgetUpdated(t, "WIDG4")
 }
 
+func TestCreateResult(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	result, err := tdb.MakeRecord(map[string]interface{}{"sku": "RESULT1"}, "widgets").CreateResult()
+	if err != nil {
+		t.Error(err)
+	}
+	if result.LastInsertID < 1 || result.RowsAffected != 1 {
+		t.Errorf("unexpected result %+v", result)
+	}
+}
+
+func TestOmitZero(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	var description *string
+	_, err := tdb.MakeRecord(map[string]interface{}{
+		"sku":         "WIDG2",
+		"description": description,
+		"weight":      0.0,
+		"updated_at":  time.Now().UTC(),
+	}, "widgets").OmitZero().Update("sku")
+	if err != nil {
+		t.Error(err)
+	}
+	widget, err := tdb.RowByStringField("select * from widgets where sku = ?", "WIDG2")
+	if err != nil {
+		t.Error(err)
+	}
+	if widget["description"] != "Widget Two" {
+		t.Errorf("expected description to be left as 'Widget Two', got %v", widget["description"])
+	}
+	if weight, ok := widget["weight"].(float64); !ok || math.Abs(weight-34.5) > 0.1 {
+		t.Errorf("expected weight to be left as 34.5, got %v", widget["weight"])
+	}
+}
+
 func bootstrap() error {
 	configs := getConfigs(true)
 	d, err := MakeSchemaless(configs)
@@ -395,7 +638,7 @@ func seedWidgetsTable() error {
 }
 
 func checkRows(t *testing.T, rows *sql.Rows) {
-	mappedRows, err := parseRowResults(rows)
+	mappedRows, err := tdb.parseRowResults(rows, QueryOptions{})
 	if err != nil {
 		t.Error(err)
 	}
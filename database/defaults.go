@@ -0,0 +1,14 @@
+package database
+
+// sets the table's defaults for columns the record doesn't have
+func (r *Record) applyDefaults() {
+	for column, value := range r.database.configs.Defaults[r.table] {
+		if _, ok := r.properties[column]; ok {
+			continue
+		}
+		if generate, ok := value.(func() interface{}); ok {
+			value = generate()
+		}
+		r.Set(column, value)
+	}
+}
@@ -0,0 +1,63 @@
+package database
+
+import (
+	"testing"
+)
+
+func TestApplyDefaults(t *testing.T) {
+	generated := 0
+	d := &Database{configs: &Configs{Defaults: map[string]map[string]interface{}{
+		"orders": {
+			"status": "pending",
+			"reference": func() interface{} {
+				generated++
+				return generated
+			},
+		},
+	}}}
+	record := d.MakeRecord(map[string]interface{}{"total": 10.0}, "orders")
+	record.applyDefaults()
+	if status, _ := record.GetString("status"); status != "pending" {
+		t.Errorf("expected the status to default to pending, got %s", status)
+	}
+	if reference, _ := record.Property("reference"); reference != 1 {
+		t.Errorf("expected the reference to be generated, got %v", reference)
+	}
+	record = d.MakeRecord(map[string]interface{}{"status": "paid", "reference": nil}, "orders")
+	record.applyDefaults()
+	if status, _ := record.GetString("status"); status != "paid" {
+		t.Errorf("expected a set status to be kept, got %s", status)
+	}
+	if reference, _ := record.Property("reference"); reference != nil || generated != 1 {
+		t.Errorf("expected a column set to nil to be kept, got %v", reference)
+	}
+	other := d.MakeRecord(map[string]interface{}{}, "customers")
+	other.applyDefaults()
+	if len(other.properties) > 0 {
+		t.Errorf("expected tables without defaults to be left alone, got %v", other.properties)
+	}
+}
+
+func TestDefaultsOnCreate(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	configs := getConfigs(false)
+	configs.Defaults = map[string]map[string]interface{}{"widgets": {"description": "Default Widget"}}
+	d, err := Make(configs)
+	if err != nil {
+		t.Error(err)
+	}
+	defer d.Close()
+	record := d.MakeRecord(map[string]interface{}{"sku": "DEFAULT1"}, "widgets")
+	_, err = record.Save()
+	if err != nil {
+		t.Error(err)
+	}
+	err = record.Refresh()
+	if err != nil {
+		t.Error(err)
+	}
+	if description, _ := record.GetString("description"); description != "Default Widget" {
+		t.Errorf("expected the description to default, got %s", description)
+	}
+}
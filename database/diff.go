@@ -0,0 +1,87 @@
+package database
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// RowDiff is a row found in both result sets whose columns differ
+type RowDiff struct {
+	Key     map[string]interface{}
+	Changes map[string]Change
+}
+
+// ResultDiff is the difference between two query results. Added rows are only in the second
+// result, removed rows only in the first
+type ResultDiff struct {
+	Added   []map[string]interface{}
+	Removed []map[string]interface{}
+	Changed []RowDiff
+}
+
+// Equal reports whether the results held the same rows
+func (r ResultDiff) Equal() bool {
+	return len(r.Added) < 1 && len(r.Removed) < 1 && len(r.Changed) < 1
+}
+
+// DiffResults compares two query results, matching rows on the key columns, which should be unique
+// within each result. A column missing from one side of a row compares as nil
+func DiffResults(a, b []map[string]interface{}, keyColumns []string) ResultDiff {
+	var diff ResultDiff
+	matched := make(map[string]map[string]interface{}, len(b))
+	for _, row := range b {
+		matched[rowKey(row, keyColumns)] = row
+	}
+	seen := make(map[string]bool, len(a))
+	for _, row := range a {
+		key := rowKey(row, keyColumns)
+		seen[key] = true
+		other, ok := matched[key]
+		if !ok {
+			diff.Removed = append(diff.Removed, row)
+			continue
+		}
+		changes := diffRow(row, other)
+		if len(changes) > 0 {
+			diff.Changed = append(diff.Changed, RowDiff{Key: keyValues(row, keyColumns), Changes: changes})
+		}
+	}
+	for _, row := range b {
+		if !seen[rowKey(row, keyColumns)] {
+			diff.Added = append(diff.Added, row)
+		}
+	}
+	return diff
+}
+
+func diffRow(from, to map[string]interface{}) map[string]Change {
+	changes := make(map[string]Change)
+	for column, value := range from {
+		if !reflect.DeepEqual(value, to[column]) {
+			changes[column] = Change{From: value, To: to[column]}
+		}
+	}
+	for column, value := range to {
+		if _, ok := from[column]; !ok && value != nil {
+			changes[column] = Change{From: nil, To: value}
+		}
+	}
+	return changes
+}
+
+func keyValues(row map[string]interface{}, keyColumns []string) map[string]interface{} {
+	values := make(map[string]interface{}, len(keyColumns))
+	for _, column := range keyColumns {
+		values[column] = row[column]
+	}
+	return values
+}
+
+// identifies a row by its key values, including their types so 1 and "1" don't match
+func rowKey(row map[string]interface{}, keyColumns []string) string {
+	values := make([]interface{}, len(keyColumns))
+	for i, column := range keyColumns {
+		values[i] = row[column]
+	}
+	return fmt.Sprintf("%#v", values)
+}
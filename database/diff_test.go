@@ -0,0 +1,46 @@
+package database
+
+import (
+	"testing"
+)
+
+func TestDiffResults(t *testing.T) {
+	before := []map[string]interface{}{
+		{"id": int64(1), "name": "Kept", "total": 10.0},
+		{"id": int64(2), "name": "Changed", "total": 20.0},
+		{"id": int64(3), "name": "Removed", "total": 30.0},
+	}
+	after := []map[string]interface{}{
+		{"id": int64(4), "name": "Added", "total": 40.0},
+		{"id": int64(2), "name": "Changed", "total": 25.0},
+		{"id": int64(1), "name": "Kept", "total": 10.0},
+	}
+	diff := DiffResults(before, after, []string{"id"})
+	if diff.Equal() {
+		t.Fatalf("expected the results to differ")
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0]["name"] != "Removed" {
+		t.Errorf("unexpected removed rows %v", diff.Removed)
+	}
+	if len(diff.Added) != 1 || diff.Added[0]["name"] != "Added" {
+		t.Errorf("unexpected added rows %v", diff.Added)
+	}
+	if len(diff.Changed) != 1 {
+		t.Fatalf("expected one changed row, got %v", diff.Changed)
+	}
+	changed := diff.Changed[0]
+	if changed.Key["id"] != int64(2) || len(changed.Changes) != 1 || changed.Changes["total"] != (Change{From: 20.0, To: 25.0}) {
+		t.Errorf("unexpected changed row %+v", changed)
+	}
+	if !DiffResults(before, before, []string{"id"}).Equal() {
+		t.Errorf("expected identical results to be equal")
+	}
+	typed := DiffResults(
+		[]map[string]interface{}{{"id": int64(1)}},
+		[]map[string]interface{}{{"id": "1"}},
+		[]string{"id"},
+	)
+	if len(typed.Added) != 1 || len(typed.Removed) != 1 {
+		t.Errorf("expected keys of different types not to match, got %+v", typed)
+	}
+}
@@ -0,0 +1,89 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+// MirrorStats counts the statements a DualWrite handle has mirrored to its secondary
+type MirrorStats struct {
+	// Queued statements were handed to the secondary's async queue
+	Queued uint64
+	// Dropped statements were not mirrored because the queue was full
+	Dropped uint64
+	// Failed statements were queued but could not be written to the secondary
+	Failed uint64
+}
+
+// statements whose insert id the secondary should reuse
+var insertStatement = regexp.MustCompile(`(?i)^\s*(INSERT|REPLACE)\b`)
+
+type mirror struct {
+	primary   string
+	secondary *Database
+	queued    atomic.Uint64
+	dropped   atomic.Uint64
+	failed    atomic.Uint64
+	sampler   writeSampler
+}
+
+// DualWrite returns a handle on the same connection that also mirrors successful writes made through
+// Exec, including Record writes, to the secondary for live migrations between servers. Reads stay
+// on this database. Mirroring is best-effort through the secondary's async queue, enabled with the
+// options. Close the secondary to flush what is queued. Statements naming this database's schema, as
+// Records do, are rewritten to name the secondary's, and inserts reuse the primary's AUTO_INCREMENT id
+func (d *Database) DualWrite(secondary *Database, options AsyncOptions) *Database {
+	m := &mirror{primary: d.Name(), secondary: secondary}
+	onError := options.OnError
+	options.OnError = func(query string, inserts []interface{}, err error) {
+		m.failed.Add(1)
+		if onError != nil {
+			onError(query, inserts, err)
+		}
+	}
+	secondary.EnableAsync(options)
+	mirrored := *d
+	mirrored.mirror = m
+	return &mirrored
+}
+
+// MirrorStats returns the counts of mirrored writes for a DualWrite handle
+func (d *Database) MirrorStats() MirrorStats {
+	if d.mirror == nil {
+		return MirrorStats{}
+	}
+	return MirrorStats{
+		Queued:  d.mirror.queued.Load(),
+		Dropped: d.mirror.dropped.Load(),
+		Failed:  d.mirror.failed.Load(),
+	}
+}
+
+// queues a successful write for the secondary
+func (m *mirror) write(ctx context.Context, query string, inserts []interface{}, result sql.Result) {
+	statement := asyncStatement{query: m.rename(query), inserts: inserts}
+	statement.traceID, _ = TraceID(ctx)
+	if insertStatement.MatchString(query) {
+		if id, err := result.LastInsertId(); err == nil && id > 0 {
+			statement.insertID = id
+		}
+	}
+	err := m.secondary.execAsync(statement)
+	if err != nil {
+		m.dropped.Add(1)
+		return
+	}
+	m.queued.Add(1)
+}
+
+// names the secondary's schema where the statement names the primary's
+func (m *mirror) rename(query string) string {
+	secondary := m.secondary.Name()
+	if len(m.primary) < 1 || m.primary == secondary {
+		return query
+	}
+	return strings.ReplaceAll(query, quoteIdentifier(m.primary)+".", quoteIdentifier(secondary)+".")
+}
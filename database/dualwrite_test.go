@@ -0,0 +1,122 @@
+package database
+
+import (
+	"testing"
+)
+
+func TestDualWrite(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	_, err := tdb.Exec("CREATE DATABASE IF NOT EXISTS dual_write_mirror", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	for _, table := range []string{"mirrored_widgets", "dual_write_mirror.mirrored_widgets"} {
+		_, err = tdb.Exec("CREATE TABLE IF NOT EXISTS "+table+" (id INT AUTO_INCREMENT PRIMARY KEY, sku VARCHAR(100))", nil)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	// put the secondary's AUTO_INCREMENT ahead of the primary's
+	_, err = tdb.Exec("INSERT INTO dual_write_mirror.mirrored_widgets (sku) VALUES ('SECONDARY0')", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	configs := getConfigs(false)
+	configs.Database = "dual_write_mirror"
+	secondary, err := Make(configs)
+	if err != nil {
+		t.Error(err)
+	}
+	var failures int
+	primary := tdb.DualWrite(&secondary, AsyncOptions{
+		OnError: func(query string, inserts []interface{}, err error) {
+			failures++
+		},
+	})
+	first, err := primary.MakeRecord(map[string]interface{}{"sku": "MIRROR1"}, "mirrored_widgets").Create()
+	if err != nil {
+		t.Error(err)
+	}
+	result, err := primary.Exec("insert into mirrored_widgets (sku) values (?)", []interface{}{"MIRROR2"})
+	if err != nil {
+		t.Error(err)
+	}
+	second, _ := result.LastInsertId()
+	secondary.FlushAsync()
+	stats := primary.MirrorStats()
+	if stats.Queued != 2 || stats.Dropped != 0 || failures != 0 {
+		t.Errorf("unexpected mirror stats %+v with %d failures", stats, failures)
+	}
+	for _, sku := range []string{"MIRROR1", "MIRROR2"} {
+		rows, err := tdb.QueryRaw("select count(*) as total from mirrored_widgets where sku = ?", []interface{}{sku})
+		if err != nil {
+			t.Error(err)
+		}
+		if rows[0]["total"] != int64(1) {
+			t.Errorf("expected one %s row on the primary, got %v", sku, rows[0]["total"])
+		}
+		rows, err = tdb.QueryRaw("select count(*) as total from dual_write_mirror.mirrored_widgets where sku = ?", []interface{}{sku})
+		if err != nil {
+			t.Error(err)
+		}
+		if rows[0]["total"] != int64(1) {
+			t.Errorf("expected one %s row on the secondary, got %v", sku, rows[0]["total"])
+		}
+	}
+	for sku, id := range map[string]int64{"MIRROR1": first, "MIRROR2": second} {
+		rows, err := tdb.QueryRaw("select sku from dual_write_mirror.mirrored_widgets where id = ?", []interface{}{id})
+		if err != nil {
+			t.Error(err)
+		}
+		if len(rows) != 1 || rows[0]["sku"] != sku {
+			t.Errorf("expected the secondary's %s row to keep id %d, got %v", sku, id, rows)
+		}
+	}
+	_, err = primary.RunScript([]ScriptStep{
+		{Query: "insert into mirrored_widgets (sku) values (?)", Args: []interface{}{"SCRIPTED1"}},
+		{Query: "insert into missing_table (sku) values (?)", Args: []interface{}{"SCRIPTED2"}},
+	})
+	if err == nil {
+		t.Errorf("expected the script to fail")
+	}
+	_, err = primary.RunScript([]ScriptStep{
+		{Query: "insert into mirrored_widgets (sku) values (?)", Args: []interface{}{"SCRIPTED3"}},
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	secondary.FlushAsync()
+	for sku, want := range map[string]int64{"SCRIPTED1": 0, "SCRIPTED3": 1} {
+		rows, err := tdb.QueryRaw("select count(*) as total from dual_write_mirror.mirrored_widgets where sku = ?", []interface{}{sku})
+		if err != nil {
+			t.Error(err)
+		}
+		if rows[0]["total"] != want {
+			t.Errorf("expected %d %s rows on the secondary, got %v", want, sku, rows[0]["total"])
+		}
+	}
+	_, err = primary.Exec("insert into missing_table (sku) values (?)", []interface{}{"MIRROR3"})
+	if err == nil {
+		t.Errorf("expected the primary write to fail")
+	}
+	secondary.Close()
+	if failures != 0 || primary.MirrorStats().Queued != 3 {
+		t.Errorf("expected failed primary writes not to be mirrored, got %+v", primary.MirrorStats())
+	}
+	if tdb.MirrorStats() != (MirrorStats{}) {
+		t.Errorf("expected the original handle not to mirror")
+	}
+}
+
+func TestMirrorRename(t *testing.T) {
+	m := &mirror{primary: "app", secondary: &Database{configs: &Configs{Database: "app_next"}}}
+	query := m.rename("UPDATE `app`.`users` SET `name` = ? WHERE `app`.`users`.`id` = ?")
+	if query != "UPDATE `app_next`.`users` SET `name` = ? WHERE `app_next`.`users`.`id` = ?" {
+		t.Errorf("unexpected mirrored statement %s", query)
+	}
+	m.primary = "app_next"
+	if query = m.rename("DELETE FROM `app_next`.`users`"); query != "DELETE FROM `app_next`.`users`" {
+		t.Errorf("expected statements for a secondary with the same schema name to be unchanged, got %s", query)
+	}
+}
@@ -0,0 +1,128 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Relation describes how a table's rows relate to rows of another table
+type Relation struct {
+	Table      string
+	ForeignKey string
+	// BelongsTo puts the foreign key on the parent table, referencing the related table's id.
+	// Otherwise the related table's foreign key references the parent's id, as with HasMany
+	BelongsTo bool
+}
+
+type relationRegistry struct {
+	mu     sync.RWMutex
+	tables map[string]map[string]Relation
+}
+
+// RegisterRelation names a relation of the table for With to load, e.g. "orders" on "users"
+func (d *Database) RegisterRelation(table, name string, relation Relation) {
+	d.relations.mu.Lock()
+	defer d.relations.mu.Unlock()
+	if d.relations.tables[table] == nil {
+		d.relations.tables[table] = make(map[string]Relation)
+	}
+	d.relations.tables[table][name] = relation
+}
+
+// With loads the named relations of rows fetched from the table and attaches them to each row under
+// the relation's name, fetching each relation with a single WHERE IN query rather than a query per
+// row. Nested relations are named by path, e.g. With(users, "users", "orders", "orders.items").
+// HasMany relations attach a slice of rows and BelongsTo relations a row, or nil
+func (d *Database) With(rows []map[string]interface{}, table string, relations ...string) error {
+	return d.eagerLoad(rows, table, relationTree(relations))
+}
+
+// groups relation paths by their first name, e.g. "orders" -> ["items"] for "orders.items"
+func relationTree(paths []string) map[string][]string {
+	tree := make(map[string][]string)
+	for _, path := range paths {
+		name, rest, nested := strings.Cut(path, ".")
+		if nested {
+			tree[name] = append(tree[name], rest)
+		} else if _, ok := tree[name]; !ok {
+			tree[name] = nil
+		}
+	}
+	return tree
+}
+
+func (d *Database) eagerLoad(rows []map[string]interface{}, table string, tree map[string][]string) error {
+	if len(rows) < 1 {
+		return nil
+	}
+	for name, nested := range tree {
+		d.relations.mu.RLock()
+		relation, ok := d.relations.tables[table][name]
+		d.relations.mu.RUnlock()
+		if !ok {
+			return fmt.Errorf("relation %s is not registered on %s", name, table)
+		}
+		related, err := d.loadRelation(rows, name, relation)
+		if err != nil {
+			return err
+		}
+		err = d.eagerLoad(related, relation.Table, relationTree(nested))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fetches a relation for all of the rows, attaches it and returns the related rows
+func (d *Database) loadRelation(rows []map[string]interface{}, name string, relation Relation) ([]map[string]interface{}, error) {
+	localKey, remoteKey := defaultKey, relation.ForeignKey
+	if relation.BelongsTo {
+		localKey, remoteKey = relation.ForeignKey, defaultKey
+	}
+	var keys []interface{}
+	seen := make(map[string]bool)
+	for _, row := range rows {
+		key := row[localKey]
+		if key == nil || seen[fmt.Sprint(key)] {
+			continue
+		}
+		seen[fmt.Sprint(key)] = true
+		keys = append(keys, key)
+	}
+	var related []map[string]interface{}
+	if len(keys) > 0 {
+		where, values, err := d.scope(
+			quoteIdentifier(remoteKey)+" IN ("+strings.TrimRight(strings.Repeat("?, ", len(keys)), ", ")+")",
+			keys,
+		)
+		if err != nil {
+			return nil, err
+		}
+		related, err = d.QueryRaw("SELECT * FROM "+quoteIdentifier(d.Name())+"."+quoteIdentifier(relation.Table)+" WHERE "+where, values)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// keys are matched by their text, as the two sides of a relation can scan as different types
+	grouped := make(map[string][]map[string]interface{})
+	for _, row := range related {
+		key := fmt.Sprint(row[remoteKey])
+		grouped[key] = append(grouped[key], row)
+	}
+	for _, row := range rows {
+		matches := grouped[fmt.Sprint(row[localKey])]
+		if row[localKey] == nil {
+			matches = nil
+		}
+		if !relation.BelongsTo {
+			row[name] = append([]map[string]interface{}{}, matches...)
+		} else if len(matches) > 0 {
+			row[name] = matches[0]
+		} else {
+			row[name] = nil
+		}
+	}
+	return related, nil
+}
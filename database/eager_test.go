@@ -0,0 +1,85 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRelationTree(t *testing.T) {
+	tree := relationTree([]string{"orders.items", "orders", "customer", "orders.items.product"})
+	expected := map[string][]string{
+		"orders":   {"items", "items.product"},
+		"customer": nil,
+	}
+	if !reflect.DeepEqual(tree, expected) {
+		t.Errorf("expected %v, got %v", expected, tree)
+	}
+}
+
+func TestWith(t *testing.T) {
+	defer recovery(t)
+	createRelationTables(t)
+	_, err := tdb.Exec("CREATE TABLE IF NOT EXISTS order_items_rel (id INT AUTO_INCREMENT PRIMARY KEY, order_id INT, sku VARCHAR(100))", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	d, err := Make(getConfigs(false))
+	if err != nil {
+		t.Error(err)
+	}
+	defer d.Close()
+	d.RegisterRelation("customers_rel", "orders", Relation{Table: "orders_rel", ForeignKey: "customer_id"})
+	d.RegisterRelation("orders_rel", "items", Relation{Table: "order_items_rel", ForeignKey: "order_id"})
+	d.RegisterRelation("orders_rel", "customer", Relation{Table: "customers_rel", ForeignKey: "customer_id", BelongsTo: true})
+	var customerIDs []interface{}
+	for _, name := range []string{"Eager One", "Eager Two"} {
+		id, err := d.MakeRecord(map[string]interface{}{"name": name}, "customers_rel").Create()
+		if err != nil {
+			t.Error(err)
+		}
+		customerIDs = append(customerIDs, id)
+	}
+	orderID, err := d.MakeRecord(map[string]interface{}{"customer_id": customerIDs[0], "total": 5.0}, "orders_rel").Create()
+	if err != nil {
+		t.Error(err)
+	}
+	_, err = d.MakeRecords([]map[string]interface{}{
+		{"order_id": orderID, "sku": "EAGER1"},
+		{"order_id": orderID, "sku": "EAGER2"},
+	}, "order_items_rel").Create()
+	if err != nil {
+		t.Error(err)
+	}
+	customers, err := d.QueryRaw("select * from customers_rel where id in (?, ?) order by id", customerIDs)
+	if err != nil {
+		t.Error(err)
+	}
+	err = d.With(customers, "customers_rel", "orders", "orders.items", "orders.customer")
+	if err != nil {
+		t.Error(err)
+	}
+	orders, ok := customers[0]["orders"].([]map[string]interface{})
+	if !ok || len(orders) != 1 {
+		t.Fatalf("expected the first customer to have one order, got %v", customers[0]["orders"])
+	}
+	if items, _ := orders[0]["items"].([]map[string]interface{}); len(items) != 2 {
+		t.Errorf("expected the order to have two items, got %v", orders[0]["items"])
+	}
+	if owner, _ := orders[0]["customer"].(map[string]interface{}); owner["name"] != "Eager One" {
+		t.Errorf("expected the order to belong to Eager One, got %v", orders[0]["customer"])
+	}
+	if others, _ := customers[1]["orders"].([]map[string]interface{}); len(others) != 0 {
+		t.Errorf("expected the second customer to have no orders, got %v", customers[1]["orders"])
+	}
+	d.RegisterScope("without_eager2", "sku <> ?", "EAGER2")
+	err = d.Scoped("without_eager2").With(orders, "orders_rel", "items")
+	if err != nil {
+		t.Error(err)
+	}
+	if items, _ := orders[0]["items"].([]map[string]interface{}); len(items) != 1 || items[0]["sku"] != "EAGER1" {
+		t.Errorf("expected a scoped handle to eager load only the items in scope, got %v", orders[0]["items"])
+	}
+	if err = d.With(customers, "customers_rel", "invoices"); err == nil {
+		t.Errorf("expected an error for a relation that is not registered")
+	}
+}
@@ -0,0 +1,136 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// enumColumn holds the permitted values of an ENUM or SET column
+type enumColumn struct {
+	set    bool
+	values []string
+}
+
+// enumCache holds the ENUM and SET columns of each table, looked up once per Database
+type enumCache struct {
+	mu     sync.Mutex
+	tables map[string]map[string]enumColumn
+}
+
+func newEnumCache() *enumCache {
+	return &enumCache{tables: make(map[string]map[string]enumColumn)}
+}
+
+// EnumValues returns the permitted values of an ENUM or SET column
+func (d *Database) EnumValues(table, column string) ([]string, error) {
+	columns, err := d.enumColumns(table)
+	if err != nil {
+		return nil, err
+	}
+	enum, ok := columns[column]
+	if !ok {
+		return nil, fmt.Errorf("%s.%s is not an ENUM or SET column", table, column)
+	}
+	return append([]string{}, enum.values...), nil
+}
+
+// ValidateEnums checks the Record's ENUM and SET properties against the values the columns permit,
+// returning an error naming the permitted values rather than letting MySQL truncate the value
+func (r *Record) ValidateEnums() error {
+	columns, err := r.database.enumColumns(r.table)
+	if err != nil {
+		return err
+	}
+	for field, value := range r.properties {
+		enum, ok := columns[field]
+		if !ok || value == nil {
+			continue
+		}
+		text := fmt.Sprint(r.normalize(value))
+		candidates := []string{text}
+		if enum.set {
+			if len(text) < 1 {
+				continue
+			}
+			candidates = strings.Split(text, ",")
+		}
+		for _, candidate := range candidates {
+			if !enum.permits(candidate) {
+				return fmt.Errorf("'%s' is not a permitted value for %s.%s; expected one of '%s'",
+					candidate, r.table, field, strings.Join(enum.values, "', '"))
+			}
+		}
+	}
+	return nil
+}
+
+// MySQL compares ENUM and SET values case-insensitively under the default collations
+func (e enumColumn) permits(value string) bool {
+	for _, permitted := range e.values {
+		if strings.EqualFold(permitted, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *Database) enumColumns(table string) (map[string]enumColumn, error) {
+	d.enums.mu.Lock()
+	defer d.enums.mu.Unlock()
+	if columns, ok := d.enums.tables[table]; ok {
+		return columns, nil
+	}
+	rows, err := d.QueryRaw(
+		"SELECT column_name AS column_name, column_type AS column_type FROM information_schema.columns WHERE table_schema = ? AND table_name = ? AND data_type IN ('enum', 'set')",
+		[]interface{}{d.Name(), table},
+	)
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]enumColumn)
+	for _, row := range rows {
+		name, _ := row["column_name"].(string)
+		columnType, _ := row["column_type"].(string)
+		enum, err := parseEnumType(columnType)
+		if err != nil {
+			return nil, err
+		}
+		columns[name] = enum
+	}
+	d.enums.tables[table] = columns
+	return columns, nil
+}
+
+// parses a column type such as enum('small','large') or set('a','b'), where quotes in values are doubled
+func parseEnumType(columnType string) (enumColumn, error) {
+	var enum enumColumn
+	open := strings.Index(columnType, "(")
+	if open < 0 || !strings.HasSuffix(columnType, ")") {
+		return enum, fmt.Errorf("cannot parse column type %s", columnType)
+	}
+	enum.set = strings.EqualFold(columnType[:open], "set")
+	list := columnType[open+1 : len(columnType)-1]
+	var value strings.Builder
+	quoted := false
+	for i := 0; i < len(list); i++ {
+		c := list[i]
+		switch {
+		case c == '\'' && quoted && i+1 < len(list) && list[i+1] == '\'':
+			value.WriteByte('\'')
+			i++
+		case c == '\'' && quoted:
+			enum.values = append(enum.values, value.String())
+			value.Reset()
+			quoted = false
+		case c == '\'':
+			quoted = true
+		case quoted:
+			value.WriteByte(c)
+		}
+	}
+	if quoted {
+		return enum, fmt.Errorf("cannot parse column type %s", columnType)
+	}
+	return enum, nil
+}
@@ -0,0 +1,89 @@
+package database
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseEnumType(t *testing.T) {
+	enum, err := parseEnumType("enum('small','it''s large','a,b')")
+	if err != nil {
+		t.Error(err)
+	}
+	if enum.set {
+		t.Errorf("expected an ENUM column")
+	}
+	expected := []string{"small", "it's large", "a,b"}
+	if !reflect.DeepEqual(enum.values, expected) {
+		t.Errorf("expected values %v, got %v", expected, enum.values)
+	}
+	enum, err = parseEnumType("set('red','green')")
+	if err != nil {
+		t.Error(err)
+	}
+	if !enum.set {
+		t.Errorf("expected a SET column")
+	}
+	if _, err = parseEnumType("enum('unterminated)"); err == nil {
+		t.Errorf("expected an error for an unterminated value")
+	}
+}
+
+func TestValidateEnums(t *testing.T) {
+	defer recovery(t)
+	_, err := tdb.Exec("CREATE TABLE IF NOT EXISTS shirts (id INT AUTO_INCREMENT PRIMARY KEY, size ENUM('small','medium','large'), colours SET('red','green','blue'))", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	values, err := tdb.EnumValues("shirts", "size")
+	if err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual(values, []string{"small", "medium", "large"}) {
+		t.Errorf("unexpected ENUM values %v", values)
+	}
+	if _, err = tdb.EnumValues("shirts", "id"); err == nil {
+		t.Errorf("expected an error for a column that is not an ENUM")
+	}
+	valid := tdb.MakeRecord(map[string]interface{}{"size": "Medium", "colours": "red,blue"}, "shirts")
+	if err = valid.ValidateEnums(); err != nil {
+		t.Error(err)
+	}
+	invalid := tdb.MakeRecord(map[string]interface{}{"size": "huge"}, "shirts")
+	err = invalid.ValidateEnums()
+	if err == nil || !strings.Contains(err.Error(), "'small', 'medium', 'large'") {
+		t.Errorf("expected an error listing the permitted values, got %v", err)
+	}
+	invalid = tdb.MakeRecord(map[string]interface{}{"colours": "red,purple"}, "shirts")
+	if err = invalid.ValidateEnums(); err == nil {
+		t.Errorf("expected an error for a SET value that is not permitted")
+	}
+}
+
+func TestValidateEnumsOnWrite(t *testing.T) {
+	defer recovery(t)
+	_, err := tdb.Exec("CREATE TABLE IF NOT EXISTS shirts (id INT AUTO_INCREMENT PRIMARY KEY, size ENUM('small','medium','large'), colours SET('red','green','blue'))", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	configs := getConfigs(false)
+	configs.ValidateEnums = true
+	d, err := Make(configs)
+	if err != nil {
+		t.Error(err)
+	}
+	defer d.Close()
+	_, err = d.MakeRecord(map[string]interface{}{"size": "huge"}, "shirts").Create()
+	if err == nil {
+		t.Errorf("expected Create to reject a value that is not permitted")
+	}
+	id, err := d.MakeRecord(map[string]interface{}{"size": "small"}, "shirts").Create()
+	if err != nil {
+		t.Error(err)
+	}
+	_, err = d.MakeRecord(map[string]interface{}{"id": id, "size": "tiny"}, "shirts").Update("id")
+	if err == nil {
+		t.Errorf("expected Update to reject a value that is not permitted")
+	}
+}
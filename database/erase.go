@@ -0,0 +1,165 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultErasureBatchSize = 500
+
+// ErasureTarget registers a table holding a data subject's personal data
+type ErasureTarget struct {
+	Table string
+	// SubjectColumn holds the data subject's key, e.g. user_id
+	SubjectColumn string
+	// KeyColumn is the table's primary key, used to batch the erasure; defaults to "id"
+	KeyColumn string
+	// Columns maps the personal data columns to their replacement values; nil nullifies the column
+	Columns map[string]interface{}
+	// Delete removes the subject's rows rather than anonymizing them
+	Delete bool
+	// BatchSize is the number of rows erased per transaction; defaults to 500
+	BatchSize int
+}
+
+// ErasureReport records what an erasure did, for compliance records
+type ErasureReport struct {
+	Subject     interface{}
+	StartedAt   time.Time
+	CompletedAt time.Time
+	Tables      []ErasureTableReport
+}
+
+// ErasureTableReport records the erasure of a subject's rows in one table
+type ErasureTableReport struct {
+	Table   string
+	Rows    int64
+	Deleted bool
+	Columns []string
+}
+
+type erasureRegistry struct {
+	mu      sync.Mutex
+	targets []ErasureTarget
+}
+
+// RegisterErasure adds a table to those Erase clears a data subject's personal data from
+func (d *Database) RegisterErasure(target ErasureTarget) error {
+	if len(target.Table) < 1 || len(target.SubjectColumn) < 1 {
+		return errors.New("erasure targets need a table and subject column")
+	}
+	if !target.Delete && len(target.Columns) < 1 {
+		return fmt.Errorf("erasure target %s has no columns to anonymize", target.Table)
+	}
+	if len(target.KeyColumn) < 1 {
+		target.KeyColumn = "id"
+	}
+	if target.BatchSize < 1 {
+		target.BatchSize = defaultErasureBatchSize
+	}
+	d.erasure.mu.Lock()
+	defer d.erasure.mu.Unlock()
+	d.erasure.targets = append(d.erasure.targets, target)
+	return nil
+}
+
+// Erase nullifies, anonymizes or deletes the subject's rows in every registered table, in batched
+// transactions. On error the report covers the batches that were committed
+func (d *Database) Erase(subjectKey interface{}) (ErasureReport, error) {
+	d.erasure.mu.Lock()
+	targets := append([]ErasureTarget{}, d.erasure.targets...)
+	d.erasure.mu.Unlock()
+	report := ErasureReport{Subject: subjectKey, StartedAt: time.Now()}
+	if len(targets) < 1 {
+		return report, errors.New("no erasure targets registered")
+	}
+	for _, target := range targets {
+		tableReport := ErasureTableReport{Table: target.Table, Deleted: target.Delete}
+		for column := range target.Columns {
+			tableReport.Columns = append(tableReport.Columns, column)
+		}
+		sort.Strings(tableReport.Columns)
+		err := d.eraseTarget(target, subjectKey, &tableReport)
+		report.Tables = append(report.Tables, tableReport)
+		if err != nil {
+			return report, err
+		}
+	}
+	report.CompletedAt = time.Now()
+	return report, nil
+}
+
+func (d *Database) eraseTarget(target ErasureTarget, subjectKey interface{}, report *ErasureTableReport) error {
+	table := quoteIdentifier(d.Name()) + "." + quoteIdentifier(target.Table)
+	rows, err := d.QueryRaw(
+		fmt.Sprintf("SELECT %s AS erasure_key FROM %s WHERE %s = ?", quoteIdentifier(target.KeyColumn), table, quoteIdentifier(target.SubjectColumn)),
+		[]interface{}{subjectKey},
+	)
+	if err != nil {
+		return err
+	}
+	var statement string
+	var assignments []interface{}
+	if target.Delete {
+		statement = "DELETE FROM " + table
+	} else {
+		var set []string
+		for _, column := range report.Columns {
+			placeholder, values, err := d.bindPlaceholder(target.Columns[column])
+			if err != nil {
+				return err
+			}
+			set = append(set, quoteIdentifier(column)+" = "+placeholder)
+			assignments = append(assignments, values...)
+		}
+		statement = "UPDATE " + table + " SET " + strings.Join(set, ", ")
+	}
+	for start := 0; start < len(rows); start += target.BatchSize {
+		end := start + target.BatchSize
+		if end > len(rows) {
+			end = len(rows)
+		}
+		keys := make([]interface{}, 0, end-start)
+		for _, row := range rows[start:end] {
+			keys = append(keys, row["erasure_key"])
+		}
+		count, err := d.eraseBatch(statement, assignments, target.KeyColumn, keys)
+		if err != nil {
+			return err
+		}
+		report.Rows += count
+	}
+	return nil
+}
+
+// erases a batch of rows in a single transaction
+func (d *Database) eraseBatch(statement string, assignments []interface{}, keyColumn string, keys []interface{}) (int64, error) {
+	placeholders := strings.TrimRight(strings.Repeat("?, ", len(keys)), ", ")
+	query := statement + " WHERE " + quoteIdentifier(keyColumn) + " IN (" + placeholders + ")"
+	connection, release := d.acquire()
+	defer release()
+	tx, err := connection.Begin()
+	if err != nil {
+		return 0, err
+	}
+	result, err := d.exec(context.Background(), tx, query, append(append([]interface{}{}, assignments...), keys...))
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		return 0, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return 0, err
+	}
+	return affected, nil
+}
@@ -0,0 +1,96 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestErase(t *testing.T) {
+	defer recovery(t)
+	_, err := tdb.Exec("CREATE TABLE IF NOT EXISTS members (id INT AUTO_INCREMENT PRIMARY KEY, subject VARCHAR(20), email VARCHAR(100), name VARCHAR(100), plan VARCHAR(20))", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	_, err = tdb.Exec("CREATE TABLE IF NOT EXISTS member_logins (id INT AUTO_INCREMENT PRIMARY KEY, subject VARCHAR(20), ip VARCHAR(45))", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	for i := 0; i < 3; i++ {
+		_, err = tdb.MakeRecord(map[string]interface{}{"subject": "S1", "email": "s1@example.com", "name": "Subject One", "plan": "gold"}, "members").Create()
+		if err != nil {
+			t.Error(err)
+		}
+		_, err = tdb.MakeRecord(map[string]interface{}{"subject": "S1", "ip": "10.0.0.1"}, "member_logins").Create()
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	_, err = tdb.MakeRecord(map[string]interface{}{"subject": "S2", "email": "s2@example.com", "name": "Subject Two", "plan": "gold"}, "members").Create()
+	if err != nil {
+		t.Error(err)
+	}
+	d, err := Make(getConfigs(false))
+	if err != nil {
+		t.Error(err)
+	}
+	defer d.Close()
+	if _, err = d.Erase("S1"); err == nil {
+		t.Errorf("expected an error when no erasure targets are registered")
+	}
+	err = d.RegisterErasure(ErasureTarget{
+		Table:         "members",
+		SubjectColumn: "subject",
+		Columns:       map[string]interface{}{"email": nil, "name": "redacted"},
+		BatchSize:     2,
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	err = d.RegisterErasure(ErasureTarget{Table: "member_logins", SubjectColumn: "subject", Delete: true})
+	if err != nil {
+		t.Error(err)
+	}
+	report, err := d.Erase("S1")
+	if err != nil {
+		t.Error(err)
+	}
+	if report.CompletedAt.IsZero() || len(report.Tables) != 2 {
+		t.Fatalf("unexpected erasure report %+v", report)
+	}
+	if report.Tables[0].Rows != 3 || !reflect.DeepEqual(report.Tables[0].Columns, []string{"email", "name"}) {
+		t.Errorf("unexpected report for members %+v", report.Tables[0])
+	}
+	if report.Tables[1].Rows != 3 || !report.Tables[1].Deleted {
+		t.Errorf("unexpected report for member_logins %+v", report.Tables[1])
+	}
+	rows, err := tdb.QueryRaw("select email, name, plan from members where subject = ?", []interface{}{"S1"})
+	if err != nil {
+		t.Error(err)
+	}
+	for _, row := range rows {
+		if row["email"] != "" || row["name"] != "redacted" || row["plan"] != "gold" {
+			t.Errorf("expected the subject's personal data to be erased, got %v", row)
+		}
+	}
+	rows, err = tdb.QueryRaw("select name from members where subject = ?", []interface{}{"S2"})
+	if err != nil {
+		t.Error(err)
+	}
+	if len(rows) != 1 || rows[0]["name"] != "Subject Two" {
+		t.Errorf("expected other subjects to be untouched, got %v", rows)
+	}
+	report, err = d.Erase("S1")
+	if err != nil {
+		t.Error(err)
+	}
+	if report.Tables[0].Rows != 0 || report.Tables[1].Rows != 0 {
+		t.Errorf("expected rows already erased not to be counted again, got %+v", report.Tables)
+	}
+	exists, err := tdb.Exists("select * from member_logins where subject = ?", []interface{}{"S1"})
+	if err != nil {
+		t.Error(err)
+	}
+	if exists {
+		t.Errorf("expected the subject's logins to be deleted")
+	}
+}
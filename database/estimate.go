@@ -0,0 +1,80 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrEstimateExceeded is returned when EXPLAIN estimates a query will read more rows than Configs.MaxEstimatedRows
+var ErrEstimateExceeded = errors.New("estimated rows exceed the configured maximum")
+
+var limitClause = regexp.MustCompile(`(?i)\bLIMIT\b`)
+
+// runs EXPLAIN on unbounded SELECTs and refuses, or reports, the ones estimated to be too large.
+// The EXPLAIN runs in the call's context, and its lane, which the caller has already acquired
+func (d *Database) checkEstimate(ctx context.Context, query string, escaped []interface{}) error {
+	if d.configs.MaxEstimatedRows < 1 || !isUnboundedSelect(query) {
+		return nil
+	}
+	plan, err := d.queryContext(ctx, "EXPLAIN "+query, escaped, QueryOptions{})
+	if err != nil {
+		return err
+	}
+	estimate := planEstimate(plan)
+	if estimate <= d.configs.MaxEstimatedRows {
+		return nil
+	}
+	if d.configs.OnEstimateExceeded != nil {
+		d.configs.OnEstimateExceeded(query, estimate)
+		return nil
+	}
+	return fmt.Errorf("%w: %d estimated, %d allowed", ErrEstimateExceeded, estimate, d.configs.MaxEstimatedRows)
+}
+
+// EstimateRows uses EXPLAIN to estimate how many rows a SELECT will produce
+func (d *Database) EstimateRows(query string, escaped []interface{}) (int64, error) {
+	plan, err := d.QueryRaw("EXPLAIN "+query, escaped)
+	if err != nil {
+		return 0, err
+	}
+	return planEstimate(plan), nil
+}
+
+// multiplies out the rows an EXPLAIN plan expects to read
+func planEstimate(plan []map[string]interface{}) int64 {
+	estimate := 1.0
+	for _, step := range plan {
+		// only the outermost select's tables multiply into the result size
+		if fmt.Sprint(step["id"]) != "1" {
+			continue
+		}
+		rows := toFloat(step["rows"])
+		filtered := toFloat(step["filtered"])
+		if filtered > 0 {
+			rows = rows * filtered / 100
+		}
+		estimate *= rows
+	}
+	return int64(estimate)
+}
+
+func isUnboundedSelect(query string) bool {
+	trimmed := strings.ToUpper(strings.TrimSpace(query))
+	return strings.HasPrefix(trimmed, "SELECT") && !limitClause.MatchString(query)
+}
+
+// converts a numeric column value of any scanned type into a float
+func toFloat(value interface{}) float64 {
+	if value == nil {
+		return 0
+	}
+	parsed, err := strconv.ParseFloat(fmt.Sprint(value), 64)
+	if err != nil {
+		return 0
+	}
+	return parsed
+}
@@ -0,0 +1,51 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMaxEstimatedRows(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	estimate, err := tdb.EstimateRows("select * from widgets", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	if estimate < 3 {
+		t.Errorf("expected at least 3 rows to be estimated, got %d", estimate)
+	}
+	configs := getConfigs(false)
+	configs.MaxEstimatedRows = 1
+	d, err := Make(configs)
+	if err != nil {
+		t.Error(err)
+	}
+	defer d.Close()
+	_, err = d.QueryRaw("select * from widgets", nil)
+	if !errors.Is(err, ErrEstimateExceeded) {
+		t.Errorf("expected ErrEstimateExceeded, got %v", err)
+	}
+	_, err = d.QueryRaw("select * from widgets limit 10", nil)
+	if err != nil {
+		t.Errorf("expected a query with a limit not to be checked, got %v", err)
+	}
+	ctx := WithTraceID(context.Background(), "estimate-check")
+	_, err = d.QueryRawWithOptions("select * from missing_estimate_table", nil, QueryOptions{Context: ctx})
+	if err == nil || !strings.Contains(err.Error(), "trace_id estimate-check") {
+		t.Errorf("expected the EXPLAIN to run with the call's context, got %v", err)
+	}
+	var reported int64
+	configs.OnEstimateExceeded = func(query string, estimate int64) {
+		reported = estimate
+	}
+	rows, err := d.QueryRaw("select * from widgets", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	if reported < 3 || len(rows) < 3 {
+		t.Errorf("expected the hook to be called and the query to run, got estimate %d and %d rows", reported, len(rows))
+	}
+}
@@ -0,0 +1,296 @@
+package database
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GeneratorOptions controls the rows generated for a table
+type GeneratorOptions struct {
+	// Seed makes the rows reproducible; the same seed and schema always generate the same rows
+	Seed int64
+	// Rows is the number of rows to generate
+	Rows int
+	// NullRate is the fraction of values, between 0 and 1, left NULL in nullable columns
+	NullRate float64
+}
+
+// a column as introspected for generating its values
+type generatorColumn struct {
+	name       string
+	dataType   string
+	columnType string
+	nullable   bool
+	unique     bool
+	maxLength  int64
+	precision  int64
+	scale      int64
+	references []interface{}
+	referenced bool
+	// offset is where unique values start, past those already in the table
+	offset int64
+}
+
+// the earliest date generated, so dates fall in a fixed window
+var generatorEpoch = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// GenerateRows makes pseudo-random rows for a table from its introspected columns, honouring their
+// types, lengths, ENUM and SET values, and uniqueness. Foreign key columns take values found in the
+// rows they reference, so those tables need seeding first. Unique values continue past the table's current
+// rows, so seeding a table again doesn't collide. Auto-increment and generated columns are left out
+func (d *Database) GenerateRows(table string, options GeneratorOptions) ([]map[string]interface{}, error) {
+	columns, err := d.generatorColumns(table)
+	if err != nil {
+		return nil, err
+	}
+	return generateRows(columns, options)
+}
+
+// SeedTable inserts generated rows into a table, returning the number inserted
+func (d *Database) SeedTable(table string, options GeneratorOptions) (int64, error) {
+	rows, err := d.GenerateRows(table, options)
+	if err != nil || len(rows) < 1 {
+		return 0, err
+	}
+	return d.MakeRecords(rows, table).Create()
+}
+
+func (d *Database) generatorColumns(table string) ([]generatorColumn, error) {
+	rows, err := d.QueryRaw(
+		`SELECT c.column_name AS column_name, c.data_type AS data_type, c.column_type AS column_type,
+			c.is_nullable = 'YES' AS nullable, c.column_key IN ('PRI', 'UNI') AS is_unique,
+			IFNULL(c.character_maximum_length, 0) AS max_length, IFNULL(c.numeric_precision, 0) AS numeric_precision,
+			IFNULL(c.numeric_scale, 0) AS numeric_scale, c.extra AS extra,
+			IFNULL(k.referenced_table_name, '') AS referenced_table, IFNULL(k.referenced_column_name, '') AS referenced_column
+		FROM information_schema.columns c
+		LEFT JOIN information_schema.key_column_usage k ON k.table_schema = c.table_schema
+			AND k.table_name = c.table_name AND k.column_name = c.column_name AND k.referenced_table_name IS NOT NULL
+		WHERE c.table_schema = ? AND c.table_name = ? ORDER BY c.ordinal_position`,
+		[]interface{}{d.Name(), table},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) < 1 {
+		return nil, fmt.Errorf("table %s not found", table)
+	}
+	var columns []generatorColumn
+	for _, row := range rows {
+		extra, _ := row["extra"].(string)
+		extra = strings.ToLower(extra)
+		if strings.Contains(extra, "auto_increment") || strings.Contains(extra, "generated") {
+			continue
+		}
+		column := generatorColumn{
+			nullable:  toFloat(row["nullable"]) != 0,
+			unique:    toFloat(row["is_unique"]) != 0,
+			maxLength: int64(toFloat(row["max_length"])),
+			precision: int64(toFloat(row["numeric_precision"])),
+			scale:     int64(toFloat(row["numeric_scale"])),
+		}
+		column.name, _ = row["column_name"].(string)
+		column.dataType, _ = row["data_type"].(string)
+		column.columnType, _ = row["column_type"].(string)
+		column.dataType = strings.ToLower(column.dataType)
+		referencedTable, _ := row["referenced_table"].(string)
+		referencedColumn, _ := row["referenced_column"].(string)
+		if len(referencedTable) > 0 {
+			column.referenced = true
+			column.references, err = d.referencedValues(referencedTable, referencedColumn)
+			if err != nil {
+				return nil, err
+			}
+		} else if column.unique {
+			column.offset, err = d.uniqueOffset(table, column)
+			if err != nil {
+				return nil, err
+			}
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+// where a unique column's generated values start: past the largest value of a numeric column, or
+// past the row count of others, whose values end in their row number
+func (d *Database) uniqueOffset(table string, column generatorColumn) (int64, error) {
+	offset := "COUNT(*)"
+	switch column.dataType {
+	case "tinyint", "smallint", "mediumint", "int", "integer", "bigint", "decimal", "float", "double":
+		offset = "IFNULL(CEIL(MAX(" + quoteIdentifier(column.name) + ")), 0)"
+	}
+	rows, err := d.QueryRaw("SELECT "+offset+" AS seeded FROM "+quoteIdentifier(d.Name())+"."+quoteIdentifier(table), nil)
+	if err != nil || len(rows) < 1 {
+		return 0, err
+	}
+	value := int64(toFloat(rows[0]["seeded"]))
+	if value < 0 {
+		return 0, nil
+	}
+	return value, nil
+}
+
+// the values a foreign key can take, in a fixed order so generation stays reproducible
+func (d *Database) referencedValues(table, column string) ([]interface{}, error) {
+	rows, err := d.QueryRaw(
+		"SELECT DISTINCT "+quoteIdentifier(column)+" AS value FROM "+quoteIdentifier(d.Name())+"."+quoteIdentifier(table)+
+			" WHERE "+quoteIdentifier(column)+" IS NOT NULL ORDER BY "+quoteIdentifier(column),
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]interface{}, 0, len(rows))
+	for _, row := range rows {
+		values = append(values, row["value"])
+	}
+	return values, nil
+}
+
+func generateRows(columns []generatorColumn, options GeneratorOptions) ([]map[string]interface{}, error) {
+	random := rand.New(rand.NewSource(options.Seed))
+	rows := make([]map[string]interface{}, 0, options.Rows)
+	for i := 0; i < options.Rows; i++ {
+		row := make(map[string]interface{}, len(columns))
+		for _, column := range columns {
+			value, err := column.generate(random, i, options.NullRate)
+			if err != nil {
+				return nil, err
+			}
+			row[column.name] = value
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// generates the column's value for the nth row
+func (c generatorColumn) generate(random *rand.Rand, n int, nullRate float64) (interface{}, error) {
+	// draw for NULL on every row so each column uses the same amount of randomness either way
+	null := random.Float64() < nullRate && c.nullable && !c.unique
+	if c.referenced {
+		if len(c.references) < 1 {
+			if c.nullable {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("%s references a table with no rows", c.name)
+		}
+		if null {
+			return nil, nil
+		}
+		return c.references[random.Intn(len(c.references))], nil
+	}
+	if null {
+		return nil, nil
+	}
+	unique := c.offset + int64(n)
+	unsigned := strings.Contains(strings.ToLower(c.columnType), "unsigned")
+	switch c.dataType {
+	case "tinyint", "smallint", "mediumint", "int", "integer", "bigint":
+		max := integerMax(c.dataType, unsigned)
+		if c.unique {
+			return unique + 1, nil
+		}
+		return random.Int63n(max) + 1, nil
+	case "bit":
+		bits := c.precision
+		if bits < 1 || bits > 63 {
+			bits = 63
+		}
+		return uint64(random.Int63n(int64(1) << bits)), nil
+	case "decimal":
+		whole := c.precision - c.scale
+		if whole > 15 {
+			whole = 15
+		}
+		value := random.Float64() * math.Pow(10, float64(whole))
+		if c.unique {
+			value = float64(unique + 1)
+		}
+		return strconv.FormatFloat(value, 'f', int(c.scale), 64), nil
+	case "float", "double":
+		if c.unique {
+			return float64(unique + 1), nil
+		}
+		return math.Round(random.Float64()*1e6) / 100, nil
+	case "char", "varchar", "tinytext", "text", "mediumtext", "longtext":
+		return c.text(random, unique), nil
+	case "binary", "varbinary", "tinyblob", "blob", "mediumblob", "longblob":
+		return []byte(c.text(random, unique)), nil
+	case "enum", "set":
+		enum, err := parseEnumType(c.columnType)
+		if err != nil {
+			return nil, err
+		}
+		if len(enum.values) < 1 {
+			return nil, fmt.Errorf("%s has no permitted values", c.name)
+		}
+		return enum.values[random.Intn(len(enum.values))], nil
+	case "date":
+		return generatorEpoch.AddDate(0, 0, random.Intn(365*25)).Format("2006-01-02"), nil
+	case "datetime", "timestamp":
+		return generatorEpoch.Add(time.Duration(random.Int63n(25*365*24*3600)) * time.Second).Format("2006-01-02 15:04:05"), nil
+	case "time":
+		return time.Duration(random.Int63n(24*3600)) * time.Second, nil
+	case "year":
+		return int64(1901 + random.Intn(255)), nil
+	case "json":
+		return fmt.Sprintf(`{"n": %d}`, random.Intn(1000)), nil
+	}
+	if c.nullable {
+		return nil, nil
+	}
+	return nil, fmt.Errorf("cannot generate values for %s, a %s column", c.name, c.dataType)
+}
+
+// random lowercase text within the column's length, suffixed with the row number when it must be unique
+func (c generatorColumn) text(random *rand.Rand, n int64) string {
+	length := c.maxLength
+	if length < 1 || length > 32 {
+		length = 32
+	}
+	size := length
+	if !strings.HasPrefix(c.dataType, "binary") && c.dataType != "char" {
+		size = 1 + random.Int63n(length)
+	}
+	text := make([]byte, size)
+	for i := range text {
+		text[i] = byte('a' + random.Intn(26))
+	}
+	if !c.unique {
+		return string(text)
+	}
+	suffix := strconv.FormatInt(n, 10)
+	if int64(len(suffix)) >= length {
+		return suffix
+	}
+	if int64(len(text)+len(suffix)) > length {
+		text = text[:length-int64(len(suffix))]
+	}
+	return string(text) + suffix
+}
+
+// the largest value generated for an integer type, kept positive and within the signed range
+func integerMax(dataType string, unsigned bool) int64 {
+	var max int64
+	switch dataType {
+	case "tinyint":
+		max = math.MaxInt8
+	case "smallint":
+		max = math.MaxInt16
+	case "mediumint":
+		max = 1<<23 - 1
+	case "int", "integer":
+		max = math.MaxInt32
+	default:
+		return math.MaxInt64 - 1
+	}
+	if unsigned {
+		max = max*2 + 1
+	}
+	return max
+}
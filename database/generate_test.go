@@ -0,0 +1,127 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+)
+
+func generatorTestColumns() []generatorColumn {
+	return []generatorColumn{
+		{name: "id", dataType: "int", unique: true},
+		{name: "sku", dataType: "varchar", columnType: "varchar(8)", unique: true, maxLength: 8},
+		{name: "description", dataType: "text", nullable: true},
+		{name: "size", dataType: "enum", columnType: "enum('small','large')"},
+		{name: "price", dataType: "decimal", precision: 6, scale: 2},
+		{name: "stock", dataType: "tinyint", columnType: "tinyint unsigned"},
+		{name: "customer_id", dataType: "int", referenced: true, references: []interface{}{int64(3), int64(7)}},
+	}
+}
+
+func TestGenerateRows(t *testing.T) {
+	options := GeneratorOptions{Seed: 42, Rows: 50, NullRate: 0.5}
+	rows, err := generateRows(generatorTestColumns(), options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := generateRows(generatorTestColumns(), options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rows, again) {
+		t.Errorf("expected the same seed to generate the same rows")
+	}
+	options.Seed = 43
+	other, err := generateRows(generatorTestColumns(), options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reflect.DeepEqual(rows, other) {
+		t.Errorf("expected a different seed to generate different rows")
+	}
+	skus := make(map[string]bool)
+	nulls := 0
+	for i, row := range rows {
+		if row["id"] != int64(i+1) {
+			t.Errorf("expected unique integers to follow the row number, got %v", row["id"])
+		}
+		sku, _ := row["sku"].(string)
+		if len(sku) < 1 || len(sku) > 8 || skus[sku] {
+			t.Errorf("expected a unique sku of at most 8 characters, got %q", sku)
+		}
+		skus[sku] = true
+		if row["description"] == nil {
+			nulls++
+		}
+		if size := row["size"]; size != "small" && size != "large" {
+			t.Errorf("unexpected size %v", size)
+		}
+		if price, _ := row["price"].(string); len(price) < 4 || price[len(price)-3] != '.' || len(price) > 7 {
+			t.Errorf("unexpected price %v", row["price"])
+		}
+		if stock, _ := row["stock"].(int64); stock < 1 || stock > 255 {
+			t.Errorf("unexpected stock %v", row["stock"])
+		}
+		if customer := row["customer_id"]; customer != int64(3) && customer != int64(7) {
+			t.Errorf("expected a referenced customer, got %v", customer)
+		}
+	}
+	if nulls == 0 || nulls == len(rows) {
+		t.Errorf("expected some descriptions to be NULL, got %d of %d", nulls, len(rows))
+	}
+	continued := generatorTestColumns()
+	continued[0].offset, continued[1].offset = 50, 50
+	more, err := generateRows(continued, GeneratorOptions{Seed: 42, Rows: 50, NullRate: 0.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range more {
+		if id, _ := row["id"].(int64); id <= 50 {
+			t.Errorf("expected unique integers to continue past the offset, got %d", id)
+		}
+		if sku, _ := row["sku"].(string); skus[sku] {
+			t.Errorf("expected unique text not to repeat an earlier run's %q", sku)
+		}
+	}
+	_, err = generateRows([]generatorColumn{{name: "customer_id", dataType: "int", referenced: true}}, GeneratorOptions{Rows: 1})
+	if err == nil {
+		t.Errorf("expected a required foreign key with nothing to reference to fail")
+	}
+}
+
+func TestSeedTable(t *testing.T) {
+	defer recovery(t)
+	createRelationTables(t)
+	_, err := tdb.SeedTable("customers_rel", GeneratorOptions{Seed: 1, Rows: 25})
+	if err != nil {
+		t.Error(err)
+	}
+	rows, err := tdb.GenerateRows("orders_rel", GeneratorOptions{Seed: 1, Rows: 10})
+	if err != nil {
+		t.Error(err)
+	}
+	if len(rows) != 10 {
+		t.Errorf("expected 10 rows, got %d", len(rows))
+	}
+	for _, row := range rows {
+		if _, ok := row["id"]; ok {
+			t.Errorf("expected the auto-increment id to be left out")
+		}
+	}
+}
+
+func TestSeedTableTwice(t *testing.T) {
+	defer recovery(t)
+	_, err := tdb.Exec("CREATE TABLE IF NOT EXISTS seeded_codes (code VARCHAR(12) NOT NULL UNIQUE, number INT NOT NULL UNIQUE)", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	for i := 0; i < 2; i++ {
+		inserted, err := tdb.SeedTable("seeded_codes", GeneratorOptions{Seed: 1, Rows: 20})
+		if err != nil {
+			t.Fatalf("expected seeding run %d to succeed, got %v", i+1, err)
+		}
+		if inserted != 20 {
+			t.Errorf("expected 20 rows, got %d", inserted)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package database
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// GetString returns a property as a string, converting []byte values
+func (r *Record) GetString(field string) (string, error) {
+	var value string
+	err := r.get(field, &value)
+	return value, err
+}
+
+// GetInt64 returns a property as an int64, converting other numeric and numeric string values
+func (r *Record) GetInt64(field string) (int64, error) {
+	var value int64
+	err := r.get(field, &value)
+	return value, err
+}
+
+// GetFloat64 returns a property as a float64, converting other numeric and numeric string values
+func (r *Record) GetFloat64(field string) (float64, error) {
+	var value float64
+	err := r.get(field, &value)
+	return value, err
+}
+
+// GetBool returns a property as a bool, treating non-zero numbers as true
+func (r *Record) GetBool(field string) (bool, error) {
+	var value bool
+	err := r.get(field, &value)
+	return value, err
+}
+
+// GetTime returns a property as a time.Time, parsing DATE and DATETIME strings
+func (r *Record) GetTime(field string) (time.Time, error) {
+	if text, ok := r.properties[field].(string); ok {
+		location := r.database.location()
+		if location == nil {
+			location = time.UTC
+		}
+		for _, layout := range []string{"2006-01-02 15:04:05.999999", "2006-01-02"} {
+			if parsed, err := time.ParseInLocation(layout, text, location); err == nil {
+				return parsed, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("property %s: cannot parse '%s' as a time", field, text)
+	}
+	var value time.Time
+	err := r.get(field, &value)
+	return value, err
+}
+
+// converts a property into the target; NULL properties give the target's zero value
+func (r *Record) get(field string, target interface{}) error {
+	value, ok := r.properties[field]
+	if !ok {
+		return fmt.Errorf("record has no property %s", field)
+	}
+	return assignValue(reflect.ValueOf(target).Elem(), field, value)
+}
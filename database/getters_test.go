@@ -0,0 +1,47 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecordGetters(t *testing.T) {
+	d := &Database{configs: &Configs{}}
+	created := time.Date(2024, time.March, 1, 9, 30, 0, 0, time.UTC)
+	record := d.MakeRecord(map[string]interface{}{
+		"name":       []byte("widget"),
+		"count":      "42",
+		"weight":     float32(1.5),
+		"active":     int64(1),
+		"created_at": created,
+		"updated_at": "2024-03-02 10:00:00",
+		"deleted_at": nil,
+	}, "widgets")
+	if name, err := record.GetString("name"); err != nil || name != "widget" {
+		t.Errorf("expected name to be widget, got %v (%v)", name, err)
+	}
+	if count, err := record.GetInt64("count"); err != nil || count != 42 {
+		t.Errorf("expected count to be 42, got %v (%v)", count, err)
+	}
+	if weight, err := record.GetFloat64("weight"); err != nil || weight != 1.5 {
+		t.Errorf("expected weight to be 1.5, got %v (%v)", weight, err)
+	}
+	if active, err := record.GetBool("active"); err != nil || !active {
+		t.Errorf("expected active to be true, got %v (%v)", active, err)
+	}
+	if at, err := record.GetTime("created_at"); err != nil || !at.Equal(created) {
+		t.Errorf("expected created_at to be %v, got %v (%v)", created, at, err)
+	}
+	if at, err := record.GetTime("updated_at"); err != nil || !at.Equal(time.Date(2024, time.March, 2, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected updated_at to be parsed, got %v (%v)", at, err)
+	}
+	if at, err := record.GetTime("deleted_at"); err != nil || !at.IsZero() {
+		t.Errorf("expected a NULL deleted_at to be the zero time, got %v (%v)", at, err)
+	}
+	if _, err := record.GetInt64("name"); err == nil {
+		t.Errorf("expected an error converting a non-numeric string")
+	}
+	if _, err := record.GetString("missing"); err == nil {
+		t.Errorf("expected an error for a missing property")
+	}
+}
@@ -0,0 +1,43 @@
+package database
+
+import (
+	"fmt"
+	"sort"
+)
+
+// drops properties the table's Fillable and Guarded lists don't allow, or errors in strict mode.
+// Only properties the caller changed are checked, and never the keys an update finds its row by;
+// on create the key is checked like any other column
+func (r *Record) guard(keys ...string) error {
+	fillable, hasFillable := r.database.configs.Fillable[r.table]
+	guarded := r.database.configs.Guarded[r.table]
+	if !hasFillable && len(guarded) < 1 {
+		return nil
+	}
+	var fields []string
+	for field := range r.properties {
+		if !contains(keys, field) && r.dirty(field) {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		if (!hasFillable || contains(fillable, field)) && !contains(guarded, field) {
+			continue
+		}
+		if r.database.configs.StrictAssignment {
+			return fmt.Errorf("%s cannot be assigned on %s", field, r.table)
+		}
+		delete(r.properties, field)
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
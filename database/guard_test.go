@@ -0,0 +1,78 @@
+package database
+
+import (
+	"testing"
+)
+
+func TestGuard(t *testing.T) {
+	d := &Database{configs: &Configs{
+		Fillable: map[string][]string{"users": {"name", "email"}},
+		Guarded:  map[string][]string{"posts": {"author_id"}},
+	}}
+	record := d.MakeRecord(map[string]interface{}{"id": 1, "name": "Jane", "is_admin": true}, "users")
+	if err := record.guard("id"); err != nil {
+		t.Error(err)
+	}
+	if _, ok := record.Property("is_admin"); ok {
+		t.Errorf("expected is_admin to be dropped")
+	}
+	if _, ok := record.Property("id"); !ok {
+		t.Errorf("expected the key to be kept")
+	}
+	record = d.MakeRecord(map[string]interface{}{"id": 1, "name": "Jane"}, "users")
+	if err := record.guard(); err != nil {
+		t.Error(err)
+	}
+	if _, ok := record.Property("id"); ok {
+		t.Errorf("expected a key that isn't fillable to be dropped on create")
+	}
+	record = d.MakeRecord(map[string]interface{}{"title": "Hello", "author_id": 7}, "posts")
+	if err := record.guard("id"); err != nil {
+		t.Error(err)
+	}
+	if _, ok := record.Property("author_id"); ok {
+		t.Errorf("expected author_id to be dropped")
+	}
+	if _, ok := record.Property("title"); !ok {
+		t.Errorf("expected title to be kept")
+	}
+	d.configs.StrictAssignment = true
+	record = d.MakeRecord(map[string]interface{}{"name": "Jane", "is_admin": true}, "users")
+	if err := record.guard("id"); err == nil {
+		t.Errorf("expected strict assignment to reject is_admin")
+	}
+	record = d.MakeRecord(map[string]interface{}{"anything": true}, "comments")
+	if err := record.guard("id"); err != nil {
+		t.Errorf("expected tables without lists to accept any column, got %v", err)
+	}
+}
+
+func TestGuardOnWrite(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	configs := getConfigs(false)
+	configs.Guarded = map[string][]string{"widgets": {"weight"}}
+	configs.StrictAssignment = true
+	d, err := Make(configs)
+	if err != nil {
+		t.Error(err)
+	}
+	defer d.Close()
+	_, err = d.MakeRecord(map[string]interface{}{"sku": "GUARD1", "weight": 100.0}, "widgets").Create()
+	if err == nil {
+		t.Errorf("expected Create to reject a guarded column")
+	}
+	id, err := tdb.MakeRecord(map[string]interface{}{"sku": "GUARD2", "weight": 100.0}, "widgets").Create()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tdb.MakeRecord(map[string]interface{}{"id": id}, "widgets").Delete("id")
+	record, err := d.FindRecord("widgets", id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = record.Set("description", "Guarded Widget").Save()
+	if err != nil {
+		t.Errorf("expected unchanged guarded columns of a loaded record to be allowed, got %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package database
+
+import (
+	"sync"
+)
+
+// HookEvent is a point in a Record's lifecycle that hooks run at
+type HookEvent int
+
+const (
+	BeforeCreate HookEvent = iota
+	AfterCreate
+	BeforeUpdate
+	AfterUpdate
+	BeforeDelete
+	AfterDelete
+)
+
+// Hook runs at a point in a Record's lifecycle, for auditing, cache invalidation or derived fields.
+// An error from a Before hook stops the write; an error from an After hook is returned once the
+// write has been made
+type Hook func(record *Record) error
+
+type hookRegistry struct {
+	mu     sync.RWMutex
+	tables map[string]map[HookEvent][]Hook
+}
+
+// RegisterHook adds a hook run for every Record of the table, before any hooks on the Record itself
+func (d *Database) RegisterHook(table string, event HookEvent, hook Hook) {
+	d.hooks.mu.Lock()
+	defer d.hooks.mu.Unlock()
+	if d.hooks.tables[table] == nil {
+		d.hooks.tables[table] = make(map[HookEvent][]Hook)
+	}
+	d.hooks.tables[table][event] = append(d.hooks.tables[table][event], hook)
+}
+
+// On adds a hook run for this record only
+func (r *Record) On(event HookEvent, hook Hook) *Record {
+	if r.hooks == nil {
+		r.hooks = make(map[HookEvent][]Hook)
+	}
+	r.hooks[event] = append(r.hooks[event], hook)
+	return r
+}
+
+func (r *Record) runHooks(event HookEvent) error {
+	r.database.hooks.mu.RLock()
+	hooks := append([]Hook{}, r.database.hooks.tables[r.table][event]...)
+	r.database.hooks.mu.RUnlock()
+	for _, hook := range append(hooks, r.hooks[event]...) {
+		err := hook(r)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete deletes the record by the id property (an empty id uses "id"), returning the rows deleted
+func (r *Record) Delete(id string) (int64, error) {
+	if len(id) < 1 {
+		id = defaultKey
+	}
+	return r.DeleteBy([]string{id})
+}
+
+// DeleteBy deletes the rows matching the record on every one of the given key columns, such as the
+// columns of a composite primary key, returning the number of rows deleted
+func (r *Record) DeleteBy(keys []string) (int64, error) {
+	if len(keys) < 1 {
+		keys = []string{defaultKey}
+	}
+	where, values, err := r.keyCondition(keys, "delete")
+	if err != nil {
+		return 0, err
+	}
+	err = r.runHooks(BeforeDelete)
+	if err != nil {
+		return 0, err
+	}
+	result, err := r.database.ExecWithOptions(
+		"DELETE FROM "+quoteIdentifier(r.database.Name())+"."+quoteIdentifier(r.table)+" WHERE "+where,
+		values,
+		r.options(),
+	)
+	if err != nil {
+		return 0, err
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return deleted, r.runHooks(AfterDelete)
+}
@@ -0,0 +1,89 @@
+package database
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestHooks(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	d, err := Make(getConfigs(false))
+	if err != nil {
+		t.Error(err)
+	}
+	defer d.Close()
+	var events []string
+	d.RegisterHook("widgets", BeforeCreate, func(record *Record) error {
+		sku, _ := record.GetString("sku")
+		record.Set("sku", strings.ToUpper(sku))
+		events = append(events, "before create")
+		return nil
+	})
+	d.RegisterHook("widgets", AfterUpdate, func(record *Record) error {
+		events = append(events, "after update")
+		return nil
+	})
+	record := d.MakeRecord(map[string]interface{}{"sku": "hooked1"}, "widgets").
+		On(AfterCreate, func(record *Record) error {
+			events = append(events, "after create")
+			return nil
+		}).
+		On(BeforeDelete, func(record *Record) error {
+			events = append(events, "before delete")
+			return nil
+		})
+	id, err := record.Save()
+	if err != nil {
+		t.Error(err)
+	}
+	_, err = record.Set("description", "Hooked Widget").Save()
+	if err != nil {
+		t.Error(err)
+	}
+	row, err := d.Row("select sku from widgets where id = ?", id)
+	if err != nil {
+		t.Error(err)
+	}
+	if row["sku"] != "HOOKED1" {
+		t.Errorf("expected the before create hook to derive the sku, got %v", row["sku"])
+	}
+	deleted, err := record.Delete("id")
+	if err != nil {
+		t.Error(err)
+	}
+	if deleted != 1 {
+		t.Errorf("expected one row to be deleted, got %d", deleted)
+	}
+	expected := []string{"before create", "after create", "after update", "before delete"}
+	if !reflect.DeepEqual(events, expected) {
+		t.Errorf("expected hooks %v, got %v", expected, events)
+	}
+}
+
+func TestBeforeHookStopsWrite(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	d, err := Make(getConfigs(false))
+	if err != nil {
+		t.Error(err)
+	}
+	defer d.Close()
+	refused := errors.New("refused")
+	_, err = d.MakeRecord(map[string]interface{}{"sku": "REFUSED1"}, "widgets").
+		On(BeforeCreate, func(record *Record) error {
+			return refused
+		}).Create()
+	if err != refused {
+		t.Errorf("expected the hook's error, got %v", err)
+	}
+	exists, err := d.Exists("select * from widgets where sku = ?", []interface{}{"REFUSED1"})
+	if err != nil {
+		t.Error(err)
+	}
+	if exists {
+		t.Errorf("expected the before create hook to stop the insert")
+	}
+}
@@ -0,0 +1,47 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrInvalidIdentifier is returned when a table or column name can't be used in generated SQL
+var ErrInvalidIdentifier = errors.New("invalid identifier")
+
+// maxIdentifierLength is the longest table or column name MySQL accepts, in characters
+const maxIdentifierLength = 64
+
+// checks a table or column name against MySQL's rules for quoted identifiers. Valid names are
+// always written through quoteIdentifier, so property names can't break out of the statement
+func validateIdentifier(name string) error {
+	switch {
+	case len(name) < 1:
+		return fmt.Errorf("%w: the name is empty", ErrInvalidIdentifier)
+	case !utf8.ValidString(name):
+		return fmt.Errorf("%w: %q is not valid UTF-8", ErrInvalidIdentifier, name)
+	case utf8.RuneCountInString(name) > maxIdentifierLength:
+		return fmt.Errorf("%w: %q is longer than %d characters", ErrInvalidIdentifier, name, maxIdentifierLength)
+	case strings.ContainsRune(name, 0):
+		return fmt.Errorf("%w: %q contains a NUL character", ErrInvalidIdentifier, name)
+	case strings.HasSuffix(name, " "):
+		return fmt.Errorf("%w: %q ends with a space", ErrInvalidIdentifier, name)
+	}
+	return nil
+}
+
+// checks the record's table and the given property names
+func (r *Record) validateIdentifiers(fields []string) error {
+	err := validateIdentifier(r.table)
+	if err != nil {
+		return err
+	}
+	for _, field := range fields {
+		err = validateIdentifier(field)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package database
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateIdentifier(t *testing.T) {
+	for _, name := range []string{"id", "created_at", "weird`name", "naïve", strings.Repeat("a", 64)} {
+		if err := validateIdentifier(name); err != nil {
+			t.Errorf("expected %q to be valid, got %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "trailing ", "nul\x00", strings.Repeat("a", 65), "\xff"} {
+		if err := validateIdentifier(name); !errors.Is(err, ErrInvalidIdentifier) {
+			t.Errorf("expected %q to be invalid, got %v", name, err)
+		}
+	}
+}
+
+func TestInsertStatementQuoting(t *testing.T) {
+	d := &Database{configs: &Configs{Database: "shop"}}
+	record := d.MakeRecord(map[string]interface{}{"name`) VALUES (1); DROP TABLE users; --": "x"}, "users")
+	statement, _, err := record.insertStatement()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if statement != "INSERT INTO `shop`.`users` (`name``) VALUES (1); DROP TABLE users; --`) VALUES (?)" {
+		t.Errorf("expected the property name to be escaped, got %s", statement)
+	}
+	record = d.MakeRecord(map[string]interface{}{"name ": "x"}, "users")
+	if _, _, err = record.insertStatement(); !errors.Is(err, ErrInvalidIdentifier) {
+		t.Errorf("expected an invalid property name to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+package database
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// Increment adds to a numeric column in the database with "col = col + ?", rather than reading and
+// writing the value, so concurrent increments aren't lost. Rows are matched on every column in
+// where, a nil value matching NULL. It returns the number of rows changed
+func (d *Database) Increment(table, column string, by interface{}, where map[string]interface{}) (int64, error) {
+	return d.adjust(table, column, "+", by, where)
+}
+
+// Decrement subtracts from a numeric column in the database, the same way as Increment
+func (d *Database) Decrement(table, column string, by interface{}, where map[string]interface{}) (int64, error) {
+	return d.adjust(table, column, "-", by, where)
+}
+
+func (d *Database) adjust(table, column, operator string, by interface{}, where map[string]interface{}) (int64, error) {
+	if len(where) < 1 {
+		return 0, errors.New("no conditions to match rows on")
+	}
+	quoted := quoteIdentifier(column)
+	condition, values := matchCondition(where)
+	inserts := append([]interface{}{by}, values...)
+	result, err := d.Exec(
+		"UPDATE "+quoteIdentifier(d.Name())+"."+quoteIdentifier(table)+" SET "+quoted+" = "+quoted+" "+operator+" ? WHERE "+condition,
+		inserts,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// builds a condition matching rows on every column in where, a nil value matching NULL
+func matchCondition(where map[string]interface{}) (string, []interface{}) {
+	columns := make([]string, 0, len(where))
+	for name := range where {
+		columns = append(columns, name)
+	}
+	sort.Strings(columns)
+	var values []interface{}
+	conditions := make([]string, 0, len(columns))
+	for _, name := range columns {
+		if where[name] == nil {
+			conditions = append(conditions, quoteIdentifier(name)+" IS NULL")
+			continue
+		}
+		conditions = append(conditions, quoteIdentifier(name)+" = ?")
+		values = append(values, where[name])
+	}
+	return strings.Join(conditions, " AND "), values
+}
@@ -0,0 +1,47 @@
+package database
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestIncrement(t *testing.T) {
+	defer recovery(t)
+	_, err := tdb.Exec("CREATE TABLE IF NOT EXISTS counters (name VARCHAR(50) PRIMARY KEY, hits INT NOT NULL DEFAULT 0)", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	_, err = tdb.MakeRecord(map[string]interface{}{"name": "increment", "hits": 0}, "counters").Create()
+	if err != nil {
+		t.Error(err)
+	}
+	where := map[string]interface{}{"name": "increment"}
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := tdb.Increment("counters", "hits", 2, where); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	affected, err := tdb.Decrement("counters", "hits", 5, where)
+	if err != nil {
+		t.Error(err)
+	}
+	if affected != 1 {
+		t.Errorf("expected one row to be changed, got %d", affected)
+	}
+	record, err := tdb.FindRecordBy("counters", where)
+	if err != nil {
+		t.Error(err)
+	}
+	if hits, _ := record.GetInt64("hits"); hits != 35 {
+		t.Errorf("expected 35 hits, got %d", hits)
+	}
+	if _, err = tdb.Increment("counters", "hits", 1, nil); err == nil {
+		t.Errorf("expected an increment without conditions to be rejected")
+	}
+}
@@ -0,0 +1,87 @@
+package database
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var inList = regexp.MustCompile(`(?i)(\bNOT\s+)?\bIN\s*\(\s*\?(?:\s*,\s*\?)*\s*\)`)
+
+// clauses whose results would change if the query were split and the results concatenated; rows
+// matched by an OR branch would come back once per chunk
+var unmergeable = regexp.MustCompile(`(?i)\b(LIMIT|GROUP\s+BY|ORDER\s+BY|DISTINCT|HAVING|UNION|OR)\b|\|\||\b(COUNT|SUM|AVG|MIN|MAX|GROUP_CONCAT)\s*\(`)
+
+var (
+	selects = regexp.MustCompile(`(?i)\bSELECT\b`)
+	inLists = regexp.MustCompile(`(?i)\bIN\s*\(`)
+)
+
+type queryChunk struct {
+	query   string
+	escaped []interface{}
+}
+
+// splits a query whose IN (?, ...) list is longer than Configs.MaxInListSize into queries over
+// chunks of the list. It returns nil when the query should run as it is
+func (d *Database) splitInList(query string, escaped []interface{}) []queryChunk {
+	size := d.configs.MaxInListSize
+	if size < 1 || unmergeable.MatchString(query) {
+		return nil
+	}
+	// a subquery or a second IN list could match the same row from several chunks
+	if len(selects.FindAllStringIndex(query, 2)) > 1 || len(inLists.FindAllStringIndex(query, 2)) > 1 {
+		return nil
+	}
+	match := inList.FindStringSubmatchIndex(query)
+	// NOT IN over chunks would match rows excluded by other chunks
+	if match == nil || match[2] >= 0 {
+		return nil
+	}
+	listed := strings.Count(query[match[0]:match[1]], "?")
+	if listed <= size {
+		return nil
+	}
+	offset := strings.Count(query[:match[0]], "?")
+	if offset+listed > len(escaped) {
+		return nil
+	}
+	return chunkInList(query, escaped, match[0], match[1], offset, listed, size)
+}
+
+// splits the list's values, without repeats, into chunks of at most size
+func chunkInList(query string, escaped []interface{}, start, end, offset, listed, size int) []queryChunk {
+	values := uniqueValues(escaped[offset : offset+listed])
+	var chunks []queryChunk
+	for from := 0; from < len(values); from += size {
+		to := from + size
+		if to > len(values) {
+			to = len(values)
+		}
+		placeholders := strings.TrimRight(strings.Repeat("?, ", to-from), ", ")
+		chunkEscaped := make([]interface{}, 0, len(escaped)-listed+to-from)
+		chunkEscaped = append(chunkEscaped, escaped[:offset]...)
+		chunkEscaped = append(chunkEscaped, values[from:to]...)
+		chunkEscaped = append(chunkEscaped, escaped[offset+listed:]...)
+		chunks = append(chunks, queryChunk{
+			query:   query[:start] + "IN (" + placeholders + ")" + query[end:],
+			escaped: chunkEscaped,
+		})
+	}
+	return chunks
+}
+
+// drops repeated values, keeping the first of each, so a row can't be matched by two chunks
+func uniqueValues(values []interface{}) []interface{} {
+	seen := make(map[string]bool, len(values))
+	unique := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		key := fmt.Sprintf("%#v", value)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, value)
+	}
+	return unique
+}
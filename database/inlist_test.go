@@ -0,0 +1,79 @@
+package database
+
+import (
+	"testing"
+)
+
+func TestSplitInList(t *testing.T) {
+	d := &Database{configs: &Configs{MaxInListSize: 2}}
+	chunks := d.splitInList("select * from widgets where weight > ? and id in (?, ?, ?) and sku <> ?", []interface{}{1, 10, 20, 30, "X"})
+	if len(chunks) != 2 {
+		t.Fatalf("expected 2 chunks, got %d", len(chunks))
+	}
+	if chunks[0].query != "select * from widgets where weight > ? and id IN (?, ?) and sku <> ?" {
+		t.Errorf("unexpected chunk query %s", chunks[0].query)
+	}
+	if len(chunks[1].escaped) != 3 || chunks[1].escaped[1] != 30 || chunks[1].escaped[2] != "X" {
+		t.Errorf("unexpected chunk args %v", chunks[1].escaped)
+	}
+	for _, query := range []string{
+		"select * from widgets where id in (?, ?, ?) order by id",
+		"select count(*) from widgets where id in (?, ?, ?)",
+		"select * from widgets where id not in (?, ?, ?)",
+		"select * from widgets where id in (?, ?)",
+		"select * from widgets where weight = ? or id in (?, ?, ?)",
+		"select * from widgets where id in (?, ?, ?) and sku in (select sku from parts)",
+		"select * from widgets where id in (?, ?, ?) and sku in ('A', 'B')",
+	} {
+		if d.splitInList(query, []interface{}{1, 2, 3}) != nil {
+			t.Errorf("expected %s not to be split", query)
+		}
+	}
+}
+
+func TestSplitInListRepeatedValues(t *testing.T) {
+	d := &Database{configs: &Configs{MaxInListSize: 2}}
+	chunks := d.splitInList("select * from widgets where id in (?, ?, ?, ?, ?)", []interface{}{10, 20, 10, 30, 20})
+	if len(chunks) != 2 {
+		t.Fatalf("expected 2 chunks, got %d", len(chunks))
+	}
+	if len(chunks[0].escaped) != 2 || chunks[0].escaped[0] != 10 || chunks[0].escaped[1] != 20 {
+		t.Errorf("unexpected first chunk args %v", chunks[0].escaped)
+	}
+	if chunks[1].query != "select * from widgets where id IN (?)" || len(chunks[1].escaped) != 1 || chunks[1].escaped[0] != 30 {
+		t.Errorf("expected repeated values to be dropped, got %s %v", chunks[1].query, chunks[1].escaped)
+	}
+}
+
+func TestMaxInListSize(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	configs := getConfigs(false)
+	configs.MaxInListSize = 1
+	d, err := Make(configs)
+	if err != nil {
+		t.Error(err)
+	}
+	defer d.Close()
+	rows, err := d.QueryRaw("select sku from widgets where sku in (?, ?, ?)", []interface{}{"WIDG1", "WIDG2", "WIDG3"})
+	if err != nil {
+		t.Error(err)
+	}
+	if len(rows) != 3 {
+		t.Errorf("expected 3 rows, got %d", len(rows))
+	}
+	rows, err = d.QueryRaw("select sku from widgets where sku in (?, ?, ?, ?)", []interface{}{"WIDG1", "WIDG2", "WIDG1", "WIDG2"})
+	if err != nil {
+		t.Error(err)
+	}
+	if len(rows) != 2 {
+		t.Errorf("expected repeated values to match their rows once, got %d rows", len(rows))
+	}
+	rows, err = d.QueryRaw("select sku from widgets where sku = ? or sku in (?, ?, ?)", []interface{}{"WIDG1", "WIDG1", "WIDG2", "WIDG3"})
+	if err != nil {
+		t.Error(err)
+	}
+	if len(rows) != 3 {
+		t.Errorf("expected an OR query to return each row once, got %d rows", len(rows))
+	}
+}
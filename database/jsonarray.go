@@ -0,0 +1,55 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// JSONArrayUpdate is a Record property that modifies a JSON array column in place
+type JSONArrayUpdate struct {
+	column string
+	value  interface{}
+	remove bool
+}
+
+// JSONAppend makes a Record property that appends a value to the JSON array in a column,
+// starting a new array if the column is NULL
+func JSONAppend(column string, value interface{}) JSONArrayUpdate {
+	return JSONArrayUpdate{column: column, value: value}
+}
+
+// JSONRemove makes a Record property that removes the first occurrence of a string from
+// the JSON array in a column, leaving the column alone when the string isn't there
+func JSONRemove(column string, value string) JSONArrayUpdate {
+	return JSONArrayUpdate{column: column, value: value, remove: true}
+}
+
+func (j JSONArrayUpdate) expression() (string, []interface{}, error) {
+	column := quoteIdentifier(j.column)
+	if j.remove {
+		// JSON_SEARCH matches like LIKE, so % and _ are escaped. CHAR(92) is the backslash EscapeLike
+		// escapes with, spelled so it reads the same with or without NO_BACKSLASH_ESCAPES
+		search := fmt.Sprintf("JSON_UNQUOTE(JSON_SEARCH(%s, 'one', ?, CHAR(92)))", column)
+		pattern := EscapeLike(j.value.(string))
+		return fmt.Sprintf("IF(%s IS NULL, %s, JSON_REMOVE(%s, %s))", search, column, column, search),
+			[]interface{}{pattern, pattern},
+			nil
+	}
+	encoded, err := json.Marshal(j.value)
+	if err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("JSON_ARRAY_APPEND(COALESCE(%s, JSON_ARRAY()), '$', CAST(? AS JSON))", column),
+		[]interface{}{string(encoded)},
+		nil
+}
+
+// JSONContains builds a WHERE condition matching rows whose JSON column contains the value,
+// e.g. JSONContains("tags", "sale") for a column holding ["new", "sale"]
+func JSONContains(column string, value interface{}) (string, []interface{}, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf("JSON_CONTAINS(%s, ?)", quoteIdentifier(column)), []interface{}{string(encoded)}, nil
+}
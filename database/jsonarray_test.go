@@ -0,0 +1,60 @@
+package database
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestJSONArrayHelpers(t *testing.T) {
+	defer recovery(t)
+	_, err := tdb.Exec("CREATE TABLE IF NOT EXISTS articles (id INT AUTO_INCREMENT PRIMARY KEY, tags JSON)", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	id, err := tdb.MakeRecord(map[string]interface{}{
+		"tags": nil,
+	}, "articles").Create()
+	if err != nil {
+		t.Error(err)
+	}
+	for _, property := range []interface{}{
+		JSONAppend("tags", "new"),
+		JSONAppend("tags", "sale"),
+		JSONAppend("tags", "50%_off"),
+		JSONRemove("tags", "new"),
+		JSONRemove("tags", "missing"),
+		JSONRemove("tags", "%"),
+		JSONRemove("tags", "s_le"),
+		JSONRemove("tags", "50%_off"),
+	} {
+		_, err = tdb.MakeRecord(map[string]interface{}{
+			"id":   id,
+			"tags": property,
+		}, "articles").Update("id")
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	row, err := tdb.Row("select tags from articles where id = ?", id)
+	if err != nil {
+		t.Error(err)
+	}
+	if row["tags"] != `["sale"]` {
+		t.Errorf(`expected tags to be ["sale"], got %v`, row["tags"])
+	}
+	expression, values, err := JSONRemove("tags", "50%_off").expression()
+	if err != nil || values[0] != `50\%\_off` || !strings.Contains(expression, "CHAR(92)") {
+		t.Errorf("expected the removed value to be LIKE-escaped, got %s %v", expression, values)
+	}
+	condition, escaped, err := JSONContains("tags", "sale")
+	if err != nil {
+		t.Error(err)
+	}
+	exists, err := tdb.Exists("select id from articles where id = ? and "+condition, append([]interface{}{id}, escaped...))
+	if err != nil {
+		t.Error(err)
+	}
+	if !exists {
+		t.Errorf("expected the article to contain the 'sale' tag")
+	}
+}
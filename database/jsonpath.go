@@ -0,0 +1,85 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// JSONTableColumn is a column extracted from each row of a JSON_TABLE
+type JSONTableColumn struct {
+	Name string
+	// Type is a MySQL column type such as "VARCHAR(100)" or "INT", or "FOR ORDINALITY"
+	Type string
+	// Path is the JSON path of the value within the row, such as "$.sku"
+	Path string
+}
+
+// JSONTable generates a MySQL 8 JSON_TABLE clause to join against
+type JSONTable struct {
+	// Document is the SQL expression holding the JSON, usually a column such as "documents.body"
+	Document string
+	// Path selects the rows within the document, such as "$.items[*]"
+	Path    string
+	Columns []JSONTableColumn
+	Alias   string
+}
+
+var jsonTableType = regexp.MustCompile(`(?i)^[a-z]+( ?\(\d+(, ?\d+)?\))?( UNSIGNED)?$`)
+
+var jsonPathOperators = map[string]bool{
+	"=": true, "!=": true, "<>": true, "<": true, "<=": true, ">": true, ">=": true, "LIKE": true, "NOT LIKE": true,
+}
+
+// SQL builds the JSON_TABLE clause; JSON_TABLE only takes literal paths, so they are quoted rather than bound
+func (j JSONTable) SQL() (string, error) {
+	if len(j.Document) < 1 || len(j.Alias) < 1 || len(j.Columns) < 1 {
+		return "", errors.New("JSON_TABLE needs a document, an alias and at least one column")
+	}
+	columns := make([]string, 0, len(j.Columns))
+	for _, column := range j.Columns {
+		if strings.EqualFold(column.Type, "FOR ORDINALITY") {
+			columns = append(columns, quoteIdentifier(column.Name)+" FOR ORDINALITY")
+			continue
+		}
+		if !jsonTableType.MatchString(column.Type) {
+			return "", fmt.Errorf("invalid JSON_TABLE column type %q", column.Type)
+		}
+		path, err := quoteString(column.Path)
+		if err != nil {
+			return "", err
+		}
+		columns = append(columns, fmt.Sprintf("%s %s PATH %s", quoteIdentifier(column.Name), column.Type, path))
+	}
+	path, err := quoteString(j.Path)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("JSON_TABLE(%s, %s COLUMNS (%s)) AS %s",
+		j.Document,
+		path,
+		strings.Join(columns, ", "),
+		quoteIdentifier(j.Alias),
+	), nil
+}
+
+// JSONPathCondition builds a WHERE condition comparing the value at a JSON path in a column,
+// e.g. JSONPathCondition("attributes", "$.colour", "=", "red")
+func JSONPathCondition(column, path, operator string, value interface{}) (string, []interface{}, error) {
+	operator = strings.ToUpper(strings.TrimSpace(operator))
+	if !jsonPathOperators[operator] {
+		return "", nil, fmt.Errorf("unsupported operator %q", operator)
+	}
+	return fmt.Sprintf("JSON_UNQUOTE(JSON_EXTRACT(%s, ?)) %s ?", quoteIdentifier(column), operator), []interface{}{path, value}, nil
+}
+
+// quotes a string literal for places where MySQL does not accept placeholders. Single quotes are
+// doubled, which reads the same whatever the sql_mode; a backslash would be read differently with and
+// without NO_BACKSLASH_ESCAPES, so values holding one are refused
+func quoteString(value string) (string, error) {
+	if strings.Contains(value, `\`) {
+		return "", fmt.Errorf("cannot quote %q: backslashes are read differently under NO_BACKSLASH_ESCAPES", value)
+	}
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'", nil
+}
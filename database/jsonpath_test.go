@@ -0,0 +1,91 @@
+package database
+
+import (
+	"testing"
+)
+
+func createDocumentsTable(t *testing.T) {
+	_, err := tdb.Exec("CREATE TABLE IF NOT EXISTS documents (id INT AUTO_INCREMENT PRIMARY KEY, body JSON)", nil)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestJSONTable(t *testing.T) {
+	defer recovery(t)
+	createDocumentsTable(t)
+	id, err := tdb.MakeRecord(map[string]interface{}{
+		"body": map[string]interface{}{
+			"items": []map[string]interface{}{
+				{"sku": "WIDG1", "quantity": 2},
+				{"sku": "WIDG2", "quantity": 5},
+			},
+		},
+	}, "documents").Create()
+	if err != nil {
+		t.Error(err)
+	}
+	table, err := JSONTable{
+		Document: "documents.body",
+		Path:     "$.items[*]",
+		Alias:    "items",
+		Columns: []JSONTableColumn{
+			{Name: "position", Type: "FOR ORDINALITY"},
+			{Name: "sku", Type: "VARCHAR(100)", Path: "$.sku"},
+			{Name: "quantity", Type: "INT", Path: "$.quantity"},
+		},
+	}.SQL()
+	if err != nil {
+		t.Error(err)
+	}
+	rows, err := tdb.QueryRaw("select items.sku, items.quantity from documents, "+table+" where documents.id = ? and items.quantity > ?", []interface{}{id, 3})
+	if err != nil {
+		t.Error(err)
+	}
+	if len(rows) != 1 || rows[0]["sku"] != "WIDG2" {
+		t.Errorf("expected only WIDG2 to be returned, got %v", rows)
+	}
+	_, err = JSONTable{Document: "body", Alias: "x", Columns: []JSONTableColumn{{Name: "a", Type: "INT; DROP TABLE x", Path: "$.a"}}}.SQL()
+	if err == nil {
+		t.Errorf("expected an invalid column type to be refused")
+	}
+}
+
+func TestQuoteString(t *testing.T) {
+	quoted, err := quoteString(`$."it's"`)
+	if err != nil {
+		t.Error(err)
+	}
+	if quoted != `'$."it''s"'` {
+		t.Errorf("expected single quotes to be doubled, got %s", quoted)
+	}
+	if _, err = quoteString(`$."a\"b"`); err == nil {
+		t.Errorf("expected a backslash to be refused")
+	}
+}
+
+func TestJSONPathCondition(t *testing.T) {
+	defer recovery(t)
+	createDocumentsTable(t)
+	id, err := tdb.MakeRecord(map[string]interface{}{
+		"body": map[string]interface{}{"colour": "red"},
+	}, "documents").Create()
+	if err != nil {
+		t.Error(err)
+	}
+	condition, escaped, err := JSONPathCondition("body", "$.colour", "=", "red")
+	if err != nil {
+		t.Error(err)
+	}
+	exists, err := tdb.Exists("select id from documents where id = ? and "+condition, append([]interface{}{id}, escaped...))
+	if err != nil {
+		t.Error(err)
+	}
+	if !exists {
+		t.Errorf("expected the document to match the JSON path condition")
+	}
+	_, _, err = JSONPathCondition("body", "$.colour", "= 1 OR", "red")
+	if err == nil {
+		t.Errorf("expected an unsupported operator to be refused")
+	}
+}
@@ -0,0 +1,132 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+)
+
+// JSONSchema generates a JSON Schema for the rows of a table as this package returns them, from the
+// introspected column types, nullability and ENUM values. Columns that are NOT NULL without a
+// default are required
+func (d *Database) JSONSchema(table string) (map[string]interface{}, error) {
+	rows, err := d.QueryRaw(
+		`SELECT column_name AS column_name, data_type AS data_type, column_type AS column_type,
+			is_nullable = 'YES' AS nullable, column_default IS NOT NULL OR extra LIKE '%auto_increment%' AS has_default,
+			IFNULL(character_maximum_length, 0) AS max_length
+		FROM information_schema.columns WHERE table_schema = ? AND table_name = ? ORDER BY ordinal_position`,
+		[]interface{}{d.Name(), table},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) < 1 {
+		return nil, fmt.Errorf("table %s not found", table)
+	}
+	properties := make(map[string]interface{})
+	required := []string{}
+	for _, row := range rows {
+		name, _ := row["column_name"].(string)
+		property, err := d.columnSchema(row)
+		if err != nil {
+			return nil, err
+		}
+		properties[name] = property
+		if toFloat(row["nullable"]) == 0 && toFloat(row["has_default"]) == 0 {
+			required = append(required, name)
+		}
+	}
+	return map[string]interface{}{
+		"$schema":    "https://json-schema.org/draft/2020-12/schema",
+		"title":      table,
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}, nil
+}
+
+// OpenAPIComponents generates the schemas of the tables as OpenAPI components, keyed by table name
+func (d *Database) OpenAPIComponents(tables []string) (map[string]interface{}, error) {
+	schemas := make(map[string]interface{})
+	for _, table := range tables {
+		schema, err := d.JSONSchema(table)
+		if err != nil {
+			return nil, err
+		}
+		delete(schema, "$schema")
+		schemas[table] = schema
+	}
+	return map[string]interface{}{"schemas": schemas}, nil
+}
+
+// describes a column the way its values are returned, so it follows the type configs
+func (d *Database) columnSchema(column map[string]interface{}) (map[string]interface{}, error) {
+	dataType, _ := column["data_type"].(string)
+	columnType, _ := column["column_type"].(string)
+	dataType = strings.ToLower(dataType)
+	schema := make(map[string]interface{})
+	var jsonType string
+	switch dataType {
+	case "tinyint", "smallint", "mediumint", "int", "integer", "bigint", "bit", "year":
+		jsonType = "integer"
+		if dataType == "tinyint" && d.configs.TinyIntAsBool {
+			jsonType = "boolean"
+		}
+		if dataType == "year" && d.configs.LegacyTemporalStrings {
+			jsonType = "string"
+		}
+		// the driver reports unsigned types other than BIGINT by names with no scanner mapping
+		if dataType != "bigint" && strings.Contains(strings.ToLower(columnType), "unsigned") {
+			jsonType = "string"
+		}
+	case "float", "double", "decimal":
+		jsonType = "number"
+		if (dataType == "decimal" && d.configs.DecimalAsString) || d.TypeOptions.Floats == FloatsAsString {
+			jsonType = "string"
+		}
+	case "date":
+		jsonType = "string"
+		schema["format"] = "date"
+	case "datetime", "timestamp":
+		jsonType = "string"
+		if !d.configs.LegacyTemporalStrings {
+			schema["format"] = "date-time"
+		}
+	case "time":
+		jsonType = "string"
+		if !d.configs.LegacyTemporalStrings {
+			jsonType = "integer"
+			schema["description"] = "duration in nanoseconds"
+		}
+	case "enum":
+		jsonType = "string"
+		enum, err := parseEnumType(columnType)
+		if err != nil {
+			return nil, err
+		}
+		schema["enum"] = enum.values
+	case "binary", "varbinary", "tinyblob", "blob", "mediumblob", "longblob":
+		jsonType = "string"
+		if !d.configs.LegacyBinaryStrings {
+			schema["contentEncoding"] = "base64"
+		}
+	case "json":
+		if !d.configs.DecodeJSON {
+			jsonType = "string"
+			schema["contentMediaType"] = "application/json"
+		}
+	case "geometry", "point", "linestring", "polygon", "multipoint", "multilinestring", "multipolygon", "geometrycollection", "geomcollection":
+		jsonType = "object"
+	default:
+		jsonType = "string"
+		if maxLength := toFloat(column["max_length"]); maxLength > 0 {
+			schema["maxLength"] = int64(maxLength)
+		}
+	}
+	if len(jsonType) > 0 {
+		schema["type"] = jsonType
+		if toFloat(column["nullable"]) != 0 && d.configs.NullAsNil {
+			schema["type"] = []string{jsonType, "null"}
+		}
+	}
+	return schema, nil
+}
@@ -0,0 +1,87 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestColumnSchema(t *testing.T) {
+	d := &Database{configs: &Configs{NullAsNil: true, TinyIntAsBool: true}}
+	cases := []struct {
+		column   map[string]interface{}
+		expected map[string]interface{}
+	}{
+		{
+			map[string]interface{}{"data_type": "varchar", "column_type": "varchar(20)", "nullable": int64(0), "max_length": uint64(20)},
+			map[string]interface{}{"type": "string", "maxLength": int64(20)},
+		},
+		{
+			map[string]interface{}{"data_type": "int", "column_type": "int", "nullable": int64(1)},
+			map[string]interface{}{"type": []string{"integer", "null"}},
+		},
+		{
+			map[string]interface{}{"data_type": "int", "column_type": "int(6) unsigned", "nullable": int64(0)},
+			map[string]interface{}{"type": "string"},
+		},
+		{
+			map[string]interface{}{"data_type": "tinyint", "column_type": "tinyint(1)", "nullable": int64(0)},
+			map[string]interface{}{"type": "boolean"},
+		},
+		{
+			map[string]interface{}{"data_type": "enum", "column_type": "enum('small','large')", "nullable": int64(0)},
+			map[string]interface{}{"type": "string", "enum": []string{"small", "large"}},
+		},
+		{
+			map[string]interface{}{"data_type": "timestamp", "column_type": "timestamp", "nullable": int64(0)},
+			map[string]interface{}{"type": "string", "format": "date-time"},
+		},
+		{
+			map[string]interface{}{"data_type": "blob", "column_type": "blob", "nullable": int64(0)},
+			map[string]interface{}{"type": "string", "contentEncoding": "base64"},
+		},
+	}
+	for _, c := range cases {
+		schema, err := d.columnSchema(c.column)
+		if err != nil {
+			t.Error(err)
+		}
+		if !reflect.DeepEqual(schema, c.expected) {
+			t.Errorf("expected %s to have schema %v, got %v", c.column["column_type"], c.expected, schema)
+		}
+	}
+}
+
+func TestJSONSchema(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	schema, err := tdb.JSONSchema("widgets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schema["type"] != "object" || schema["title"] != "widgets" {
+		t.Errorf("unexpected schema %v", schema)
+	}
+	if !reflect.DeepEqual(schema["required"], []string{"sku"}) {
+		t.Errorf("expected only sku to be required, got %v", schema["required"])
+	}
+	properties, _ := schema["properties"].(map[string]interface{})
+	sku, _ := properties["sku"].(map[string]interface{})
+	if sku["type"] != "string" || sku["maxLength"] != int64(1000) {
+		t.Errorf("unexpected sku schema %v", sku)
+	}
+	weight, _ := properties["weight"].(map[string]interface{})
+	if weight["type"] != "number" {
+		t.Errorf("unexpected weight schema %v", weight)
+	}
+	components, err := tdb.OpenAPIComponents([]string{"widgets"})
+	if err != nil {
+		t.Error(err)
+	}
+	schemas, _ := components["schemas"].(map[string]interface{})
+	if _, ok := schemas["widgets"]; !ok {
+		t.Errorf("expected a widgets component, got %v", components)
+	}
+	if _, err = tdb.JSONSchema("missing_table"); err == nil {
+		t.Errorf("expected an error for a missing table")
+	}
+}
@@ -0,0 +1,45 @@
+package database
+
+import (
+	"context"
+)
+
+// Priority selects the execution lane a call runs in
+type Priority int
+
+const (
+	// Interactive is the default lane, for user-facing queries
+	Interactive Priority = iota
+	// Background is the lane for bulk and maintenance work
+	Background
+)
+
+// WithPriority makes query options that run a call in the given lane
+func WithPriority(priority Priority) QueryOptions {
+	return QueryOptions{Priority: priority}
+}
+
+// makes a semaphore for every lane that has a concurrency budget
+func newLanes(configs *Configs) map[Priority]chan struct{} {
+	lanes := make(map[Priority]chan struct{})
+	for priority, budget := range configs.Lanes {
+		if budget > 0 {
+			lanes[priority] = make(chan struct{}, budget)
+		}
+	}
+	return lanes
+}
+
+// waits for room in the call's lane, returning a func that gives the slot back
+func (d *Database) acquireLane(ctx context.Context, priority Priority) (func(), error) {
+	lane, ok := d.lanes[priority]
+	if !ok {
+		return func() {}, nil
+	}
+	select {
+	case lane <- struct{}{}:
+		return func() { <-lane }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
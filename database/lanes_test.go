@@ -0,0 +1,41 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPriorityLanes(t *testing.T) {
+	defer recovery(t)
+	configs := getConfigs(false)
+	configs.Lanes = map[Priority]int{Background: 1}
+	d, err := Make(configs)
+	if err != nil {
+		t.Error(err)
+	}
+	defer d.Close()
+	done := make(chan error)
+	go func() {
+		_, err := d.ExecWithOptions("do sleep(1)", nil, WithPriority(Background))
+		done <- err
+	}()
+	time.Sleep(200 * time.Millisecond)
+	_, err = d.QueryRawWithOptions("select 1", nil, QueryOptions{
+		Priority: Background,
+		Timeout:  100 * time.Millisecond,
+	})
+	if err == nil {
+		t.Errorf("expected the background lane to be full")
+	}
+	_, err = d.QueryRawWithOptions("select 1", nil, QueryOptions{
+		Priority: Interactive,
+		Timeout:  100 * time.Millisecond,
+	})
+	if err != nil {
+		t.Errorf("expected the interactive lane to be free, got %v", err)
+	}
+	err = <-done
+	if err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,35 @@
+package database
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// EscapeLike escapes the LIKE metacharacters % and _ (and the escape character \) in user input,
+// so it matches literally within a pattern such as "%" + EscapeLike(search) + "%"
+func EscapeLike(value string) string {
+	return likeEscaper.Replace(value)
+}
+
+// EscapeRegexp escapes regular expression metacharacters in user input for REGEXP patterns
+func EscapeRegexp(value string) string {
+	return regexp.QuoteMeta(value)
+}
+
+// LikeCondition builds a WHERE condition matching a column against a LIKE pattern
+func LikeCondition(column, pattern string) (string, []interface{}) {
+	return fmt.Sprintf("%s LIKE ?", quoteIdentifier(column)), []interface{}{pattern}
+}
+
+// NotLikeCondition builds a WHERE condition excluding rows whose column matches a LIKE pattern
+func NotLikeCondition(column, pattern string) (string, []interface{}) {
+	return fmt.Sprintf("%s NOT LIKE ?", quoteIdentifier(column)), []interface{}{pattern}
+}
+
+// RegexpCondition builds a WHERE condition matching a column against a regular expression
+func RegexpCondition(column, pattern string) (string, []interface{}) {
+	return fmt.Sprintf("%s REGEXP ?", quoteIdentifier(column)), []interface{}{pattern}
+}
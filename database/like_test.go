@@ -0,0 +1,46 @@
+package database
+
+import (
+	"testing"
+)
+
+func TestEscapeLike(t *testing.T) {
+	if EscapeLike(`50%_off\`) != `50\%\_off\\` {
+		t.Errorf(`expected 50\%%\_off\\, got %s`, EscapeLike(`50%_off\`))
+	}
+}
+
+func TestLikeConditions(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	for _, sku := range []string{"LIKE_100%", "LIKE_1000"} {
+		_, err := tdb.MakeRecord(map[string]interface{}{"sku": sku}, "widgets").Create()
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	condition, escaped := LikeCondition("sku", EscapeLike("LIKE_100%"))
+	rows, err := tdb.QueryRaw("select sku from widgets where "+condition, escaped)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(rows) != 1 || rows[0]["sku"] != "LIKE_100%" {
+		t.Errorf("expected only LIKE_100%% to match, got %v", rows)
+	}
+	condition, escaped = NotLikeCondition("sku", EscapeLike("LIKE_")+"%")
+	rows, err = tdb.QueryRaw("select sku from widgets where sku like 'LIKE%' and "+condition, escaped)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(rows) != 0 {
+		t.Errorf("expected no rows, got %v", rows)
+	}
+	condition, escaped = RegexpCondition("sku", "^"+EscapeRegexp("LIKE_100%")+"$")
+	rows, err = tdb.QueryRaw("select sku from widgets where "+condition, escaped)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(rows) != 1 {
+		t.Errorf("expected one row to match the regexp, got %v", rows)
+	}
+}
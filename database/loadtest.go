@@ -0,0 +1,205 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// LoadOperation is one kind of call in a load test's mix
+type LoadOperation struct {
+	Name string
+	// Weight is the operation's share of the mix relative to the others; defaults to 1
+	Weight int
+	Run    func(d *Database) error
+}
+
+// LoadTestOptions configures a load test
+type LoadTestOptions struct {
+	// QPS is the target rate of calls per second
+	QPS int
+	// Duration is how long calls are started for
+	Duration time.Duration
+	// Concurrency is the number of calls that can run at once; defaults to 10
+	Concurrency int
+	// Seed makes the order of operations in the mix reproducible
+	Seed       int64
+	Operations []LoadOperation
+}
+
+// LoadStats summarises the latency and errors of a set of calls
+type LoadStats struct {
+	Count  int
+	Errors int
+	P50    time.Duration
+	P95    time.Duration
+	P99    time.Duration
+	Max    time.Duration
+}
+
+// LoadTestReport is the result of a load test. Dropped counts the calls that weren't started
+// because every worker was busy, meaning the target rate couldn't be sustained
+type LoadTestReport struct {
+	Elapsed    time.Duration
+	Dropped    int
+	Total      LoadStats
+	Operations map[string]LoadStats
+}
+
+type loadSample struct {
+	operation string
+	latency   time.Duration
+	failed    bool
+}
+
+// ReadOperation makes a load test operation that runs a select query
+func ReadOperation(name, query string, args []interface{}) LoadOperation {
+	return LoadOperation{Name: name, Run: func(d *Database) error {
+		_, err := d.QueryRaw(query, args)
+		return err
+	}}
+}
+
+// WriteOperation makes a load test operation that executes a statement
+func WriteOperation(name, query string, args []interface{}) LoadOperation {
+	return LoadOperation{Name: name, Run: func(d *Database) error {
+		_, err := d.Exec(query, args)
+		return err
+	}}
+}
+
+// TransactionOperation makes a load test operation that runs a script in a transaction
+func TransactionOperation(name string, steps []ScriptStep) LoadOperation {
+	return LoadOperation{Name: name, Run: func(d *Database) error {
+		_, err := d.RunScript(steps)
+		return err
+	}}
+}
+
+// LoadTest drives a weighted mix of operations through the database at a target rate, for validating
+// pool settings and retry policies, and reports latency percentiles for each operation and overall
+func (d *Database) LoadTest(ctx context.Context, options LoadTestOptions) (LoadTestReport, error) {
+	var report LoadTestReport
+	if options.QPS < 1 || options.Duration <= 0 {
+		return report, errors.New("a load test needs a QPS and a duration")
+	}
+	mix, err := loadMix(options.Operations)
+	if err != nil {
+		return report, err
+	}
+	if options.Concurrency < 1 {
+		options.Concurrency = 10
+	}
+	calls := make(chan LoadOperation)
+	samples := make(chan loadSample, options.Concurrency)
+	var workers sync.WaitGroup
+	for i := 0; i < options.Concurrency; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for operation := range calls {
+				started := time.Now()
+				err := operation.Run(d)
+				samples <- loadSample{operation: operation.Name, latency: time.Since(started), failed: err != nil}
+			}
+		}()
+	}
+	collected := make(chan []loadSample)
+	go func() {
+		var all []loadSample
+		for sample := range samples {
+			all = append(all, sample)
+		}
+		collected <- all
+	}()
+
+	random := rand.New(rand.NewSource(options.Seed))
+	ticker := time.NewTicker(time.Second / time.Duration(options.QPS))
+	timer := time.NewTimer(options.Duration)
+	started := time.Now()
+schedule:
+	for {
+		select {
+		case <-ctx.Done():
+			break schedule
+		case <-timer.C:
+			break schedule
+		case <-ticker.C:
+			select {
+			case calls <- mix[random.Intn(len(mix))]:
+			default:
+				report.Dropped++
+			}
+		}
+	}
+	ticker.Stop()
+	timer.Stop()
+	close(calls)
+	workers.Wait()
+	close(samples)
+	all := <-collected
+	report.Elapsed = time.Since(started)
+	report.Total = loadStats(all)
+	byOperation := make(map[string][]loadSample)
+	for _, sample := range all {
+		byOperation[sample.operation] = append(byOperation[sample.operation], sample)
+	}
+	report.Operations = make(map[string]LoadStats, len(byOperation))
+	for name, operationSamples := range byOperation {
+		report.Operations[name] = loadStats(operationSamples)
+	}
+	return report, ctx.Err()
+}
+
+// expands the operations by weight, so picking one at random follows the mix
+func loadMix(operations []LoadOperation) ([]LoadOperation, error) {
+	var mix []LoadOperation
+	for _, operation := range operations {
+		if operation.Run == nil {
+			return nil, errors.New("load test operation " + operation.Name + " has nothing to run")
+		}
+		weight := operation.Weight
+		if weight < 1 {
+			weight = 1
+		}
+		for i := 0; i < weight; i++ {
+			mix = append(mix, operation)
+		}
+	}
+	if len(mix) < 1 {
+		return nil, errors.New("a load test needs at least one operation")
+	}
+	return mix, nil
+}
+
+func loadStats(samples []loadSample) LoadStats {
+	stats := LoadStats{Count: len(samples)}
+	if len(samples) < 1 {
+		return stats
+	}
+	latencies := make([]time.Duration, 0, len(samples))
+	for _, sample := range samples {
+		if sample.failed {
+			stats.Errors++
+		}
+		latencies = append(latencies, sample.latency)
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	stats.P50 = percentile(latencies, 50)
+	stats.P95 = percentile(latencies, 95)
+	stats.P99 = percentile(latencies, 99)
+	stats.Max = latencies[len(latencies)-1]
+	return stats
+}
+
+// the nearest-rank percentile of sorted latencies
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
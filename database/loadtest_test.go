@@ -0,0 +1,48 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLoadStats(t *testing.T) {
+	var samples []loadSample
+	for i := 1; i <= 100; i++ {
+		samples = append(samples, loadSample{latency: time.Duration(i) * time.Millisecond, failed: i%10 == 0})
+	}
+	stats := loadStats(samples)
+	if stats.Count != 100 || stats.Errors != 10 {
+		t.Errorf("unexpected counts %+v", stats)
+	}
+	if stats.P50 != 50*time.Millisecond || stats.P95 != 95*time.Millisecond || stats.P99 != 99*time.Millisecond || stats.Max != 100*time.Millisecond {
+		t.Errorf("unexpected percentiles %+v", stats)
+	}
+}
+
+func TestLoadTestMix(t *testing.T) {
+	d := &Database{configs: &Configs{}}
+	operations := []LoadOperation{
+		{Name: "read", Weight: 3, Run: func(*Database) error { return nil }},
+		{Name: "write", Run: func(*Database) error { return errors.New("failed") }},
+	}
+	report, err := d.LoadTest(context.Background(), LoadTestOptions{QPS: 200, Duration: 500 * time.Millisecond, Operations: operations})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reads, writes := report.Operations["read"], report.Operations["write"]
+	if reads.Count+writes.Count+report.Dropped < 50 {
+		t.Errorf("expected around 100 calls, got %+v", report)
+	}
+	if reads.Count <= writes.Count {
+		t.Errorf("expected reads to outweigh writes, got %d reads and %d writes", reads.Count, writes.Count)
+	}
+	if writes.Errors != writes.Count || reads.Errors != 0 || report.Total.Errors != writes.Count {
+		t.Errorf("unexpected errors %+v", report)
+	}
+	_, err = d.LoadTest(context.Background(), LoadTestOptions{QPS: 10, Duration: time.Second})
+	if err == nil {
+		t.Errorf("expected a load test without operations to fail")
+	}
+}
@@ -0,0 +1,160 @@
+package database
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrRequiresMariaDB is returned by MariaDB-only features when the server is MySQL
+var ErrRequiresMariaDB = errors.New("this feature requires a MariaDB server")
+
+// serverInfo caches the server's version, looked up once per Database
+type serverInfo struct {
+	mu      sync.Mutex
+	version string
+}
+
+// ServerVersion returns the server's version string, e.g. 8.0.32 or 10.11.2-MariaDB
+func (d *Database) ServerVersion() (string, error) {
+	d.server.mu.Lock()
+	defer d.server.mu.Unlock()
+	if len(d.server.version) > 0 {
+		return d.server.version, nil
+	}
+	var version string
+	connection, release := d.acquire()
+	defer release()
+	err := connection.QueryRow("SELECT VERSION()").Scan(&version)
+	if err != nil {
+		return "", err
+	}
+	d.server.version = version
+	return version, nil
+}
+
+// IsMariaDB reports whether the server is MariaDB rather than MySQL
+func (d *Database) IsMariaDB() (bool, error) {
+	version, err := d.ServerVersion()
+	if err != nil {
+		return false, err
+	}
+	return isMariaDBVersion(version), nil
+}
+
+func isMariaDBVersion(version string) bool {
+	return strings.Contains(strings.ToLower(version), "mariadb")
+}
+
+func (d *Database) requireMariaDB() error {
+	mariaDB, err := d.IsMariaDB()
+	if err != nil {
+		return err
+	}
+	if !mariaDB {
+		return ErrRequiresMariaDB
+	}
+	return nil
+}
+
+// CreateReturning creates the record with INSERT ... RETURNING on MariaDB, setting the returned columns,
+// or every column when none are given, on the record. This picks up values set by defaults, triggers
+// and sequences without a second query. Unlike Create, the insert isn't mirrored by DualWrite handles
+func (r *Record) CreateReturning(columns ...string) error {
+	err := r.database.requireMariaDB()
+	if err != nil {
+		return err
+	}
+	err = r.beforeCreate()
+	if err != nil {
+		return err
+	}
+	query, inserts, err := r.returningStatement(columns)
+	if err != nil {
+		return err
+	}
+	rows, err := r.database.QueryRawWithOptions(query, inserts, r.options())
+	if err != nil {
+		return err
+	}
+	if len(rows) > 0 {
+		for column, value := range rows[0] {
+			r.Set(column, value)
+		}
+		if err := r.hydrate(); err != nil {
+			return err
+		}
+	}
+	r.markClean()
+	return r.runHooks(AfterCreate)
+}
+
+// builds the INSERT ... RETURNING statement for the columns, or every column when none are given
+func (r *Record) returningStatement(columns []string) (string, []interface{}, error) {
+	insertStatement, inserts, err := r.insertStatement()
+	if err != nil {
+		return "", nil, err
+	}
+	returning := "*"
+	if len(columns) > 0 {
+		returning = quoteIdentifiers(columns)
+	}
+	return insertStatement + " RETURNING " + returning, inserts, nil
+}
+
+// DeleteReturning deletes the rows of a table matching every column in where with DELETE ... RETURNING
+// on MariaDB, returning the deleted rows. As it runs as a query, the delete isn't mirrored by DualWrite handles
+func (d *Database) DeleteReturning(table string, where map[string]interface{}) ([]map[string]interface{}, error) {
+	if len(where) < 1 {
+		return nil, errors.New("no conditions to match rows on")
+	}
+	err := d.requireMariaDB()
+	if err != nil {
+		return nil, err
+	}
+	return d.QueryRaw(d.deleteReturningStatement(table, where))
+}
+
+func (d *Database) deleteReturningStatement(table string, where map[string]interface{}) (string, []interface{}) {
+	condition, values := matchCondition(where)
+	return "DELETE FROM " + quoteIdentifier(d.Name()) + "." + quoteIdentifier(table) + " WHERE " + condition + " RETURNING *", values
+}
+
+// NextValue takes the next value of a MariaDB sequence
+func (d *Database) NextValue(sequence string) (int64, error) {
+	err := d.requireMariaDB()
+	if err != nil {
+		return 0, err
+	}
+	var value int64
+	connection, release := d.acquire()
+	defer release()
+	err = connection.QueryRow(d.nextValueStatement(sequence)).Scan(&value)
+	return value, err
+}
+
+func (d *Database) nextValueStatement(sequence string) string {
+	return "SELECT NEXTVAL(" + quoteIdentifier(d.Name()) + "." + quoteIdentifier(sequence) + ")"
+}
+
+// AsOfSystemTime reads the rows of a MariaDB system-versioned table as they were at a point in time,
+// matching every column in where; nil or empty where reads every row
+func (d *Database) AsOfSystemTime(table string, at time.Time, where map[string]interface{}) ([]map[string]interface{}, error) {
+	err := d.requireMariaDB()
+	if err != nil {
+		return nil, err
+	}
+	return d.QueryRaw(d.asOfSystemTimeStatement(table, at, where))
+}
+
+func (d *Database) asOfSystemTimeStatement(table string, at time.Time, where map[string]interface{}) (string, []interface{}) {
+	query := "SELECT * FROM " + quoteIdentifier(d.Name()) + "." + quoteIdentifier(table) + " FOR SYSTEM_TIME AS OF TIMESTAMP ?"
+	args := []interface{}{at}
+	if len(where) > 0 {
+		condition, values := matchCondition(where)
+		query += " WHERE " + condition
+		args = append(args, values...)
+	}
+	return query, args
+}
@@ -0,0 +1,153 @@
+package database
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIsMariaDBVersion(t *testing.T) {
+	if !isMariaDBVersion("10.11.2-MariaDB-1:10.11.2+maria~ubu2204") {
+		t.Errorf("expected a MariaDB version to be detected")
+	}
+	if isMariaDBVersion("8.0.32") {
+		t.Errorf("expected a MySQL version not to be detected as MariaDB")
+	}
+}
+
+func TestMariaDBStatements(t *testing.T) {
+	d := &Database{configs: &Configs{Database: "shop"}}
+	record := d.MakeRecord(map[string]interface{}{"sku": "RETURNING1", "weight": 2.5}, "widgets")
+	query, inserts, err := record.returningStatement([]string{"id", "created_at"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query != "INSERT INTO `shop`.`widgets` (`sku`, `weight`) VALUES (?, ?) RETURNING `id`, `created_at`" {
+		t.Errorf("unexpected INSERT ... RETURNING statement %s", query)
+	}
+	if len(inserts) != 2 || inserts[0] != "RETURNING1" || inserts[1] != 2.5 {
+		t.Errorf("unexpected insert args %v", inserts)
+	}
+	query, _, err = record.returningStatement(nil)
+	if err != nil || !strings.HasSuffix(query, " RETURNING *") {
+		t.Errorf("expected every column to be returned by default, got %s %v", query, err)
+	}
+	query, values := d.deleteReturningStatement("widgets", map[string]interface{}{"sku": "WIDG1", "description": nil})
+	if query != "DELETE FROM `shop`.`widgets` WHERE `description` IS NULL AND `sku` = ? RETURNING *" {
+		t.Errorf("unexpected DELETE ... RETURNING statement %s", query)
+	}
+	if len(values) != 1 || values[0] != "WIDG1" {
+		t.Errorf("unexpected delete args %v", values)
+	}
+	if query = d.nextValueStatement("widget_numbers"); query != "SELECT NEXTVAL(`shop`.`widget_numbers`)" {
+		t.Errorf("unexpected NEXTVAL statement %s", query)
+	}
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	query, values = d.asOfSystemTimeStatement("prices", at, map[string]interface{}{"sku": "WIDG1"})
+	if query != "SELECT * FROM `shop`.`prices` FOR SYSTEM_TIME AS OF TIMESTAMP ? WHERE `sku` = ?" {
+		t.Errorf("unexpected FOR SYSTEM_TIME statement %s", query)
+	}
+	if len(values) != 2 || values[0] != at || values[1] != "WIDG1" {
+		t.Errorf("unexpected FOR SYSTEM_TIME args %v", values)
+	}
+	query, values = d.asOfSystemTimeStatement("prices", at, nil)
+	if strings.Contains(query, "WHERE") || len(values) != 1 {
+		t.Errorf("expected no conditions without where, got %s %v", query, values)
+	}
+}
+
+func TestMariaDBFeatures(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	mariaDB, err := tdb.IsMariaDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mariaDB {
+		t.Skip("the MariaDB features are only checked against MySQL here")
+	}
+	err = tdb.MakeRecord(map[string]interface{}{"sku": "RETURNING1"}, "widgets").CreateReturning("id")
+	if !errors.Is(err, ErrRequiresMariaDB) {
+		t.Errorf("expected CreateReturning to require MariaDB, got %v", err)
+	}
+	_, err = tdb.DeleteReturning("widgets", map[string]interface{}{"sku": "WIDG1"})
+	if !errors.Is(err, ErrRequiresMariaDB) {
+		t.Errorf("expected DeleteReturning to require MariaDB, got %v", err)
+	}
+	_, err = tdb.NextValue("widget_numbers")
+	if !errors.Is(err, ErrRequiresMariaDB) {
+		t.Errorf("expected NextValue to require MariaDB, got %v", err)
+	}
+	_, err = tdb.AsOfSystemTime("widgets", time.Now(), nil)
+	if !errors.Is(err, ErrRequiresMariaDB) {
+		t.Errorf("expected AsOfSystemTime to require MariaDB, got %v", err)
+	}
+}
+
+func TestMariaDBFeaturesOnMariaDB(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	mariaDB, err := tdb.IsMariaDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !mariaDB {
+		t.Skip("needs a MariaDB server")
+	}
+	record := tdb.MakeRecord(map[string]interface{}{"sku": "RETURNING2"}, "widgets")
+	err = record.CreateReturning("id", "created_at")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, ok := record.Property("id"); !ok || id == nil {
+		t.Errorf("expected the returned id to be set on the record")
+	}
+	if _, ok := record.Property("created_at"); !ok {
+		t.Errorf("expected the returned created_at to be set on the record")
+	}
+	deleted, err := tdb.DeleteReturning("widgets", map[string]interface{}{"sku": "RETURNING2"})
+	if err != nil {
+		t.Error(err)
+	}
+	if len(deleted) != 1 || deleted[0]["sku"] != "RETURNING2" {
+		t.Errorf("expected the deleted row to be returned, got %v", deleted)
+	}
+	_, err = tdb.Exec("CREATE SEQUENCE IF NOT EXISTS widget_numbers START WITH 100 INCREMENT BY 1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := tdb.NextValue("widget_numbers")
+	if err != nil {
+		t.Error(err)
+	}
+	second, err := tdb.NextValue("widget_numbers")
+	if err != nil {
+		t.Error(err)
+	}
+	if first < 100 || second != first+1 {
+		t.Errorf("expected consecutive sequence values from 100, got %d and %d", first, second)
+	}
+	_, err = tdb.Exec("CREATE TABLE IF NOT EXISTS versioned_prices (sku VARCHAR(100), price INT) WITH SYSTEM VERSIONING", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = tdb.Exec("INSERT INTO versioned_prices (sku, price) VALUES ('AS_OF1', 1)", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	time.Sleep(1100 * time.Millisecond)
+	before := time.Now()
+	time.Sleep(1100 * time.Millisecond)
+	_, err = tdb.Exec("UPDATE versioned_prices SET price = 2 WHERE sku = 'AS_OF1'", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	rows, err := tdb.AsOfSystemTime("versioned_prices", before, map[string]interface{}{"sku": "AS_OF1"})
+	if err != nil {
+		t.Error(err)
+	}
+	if len(rows) != 1 || rows[0]["price"] != int64(1) {
+		t.Errorf("expected the price as it was before the update, got %v", rows)
+	}
+}
@@ -0,0 +1,58 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MaskFunc masks a value in query results; it is not called for NULL or empty values
+type MaskFunc func(value interface{}) interface{}
+
+// MaskingProfile maps result column names to the masking applied to them
+type MaskingProfile map[string]MaskFunc
+
+// Masked returns a handle on the same connection that masks the profile's columns in every query
+// result, so production queries can be reused without exposing raw PII. Closing either handle
+// closes the shared connection
+func (d *Database) Masked(profile MaskingProfile) *Database {
+	masked := *d
+	masked.masks = make(MaskingProfile, len(d.masks)+len(profile))
+	for col, mask := range d.masks {
+		masked.masks[col] = mask
+	}
+	for col, mask := range profile {
+		masked.masks[col] = mask
+	}
+	return &masked
+}
+
+// MaskEmail keeps the first character of the local part and the domain, e.g. j***@example.com
+func MaskEmail(value interface{}) interface{} {
+	email := maskText(value)
+	at := strings.LastIndex(email, "@")
+	if at < 1 {
+		return MaskAll(value)
+	}
+	return email[:1] + "***" + email[at:]
+}
+
+// MaskLast4 keeps only the last four characters, e.g. ************4242 for a card number
+func MaskLast4(value interface{}) interface{} {
+	runes := []rune(maskText(value))
+	if len(runes) <= 4 {
+		return strings.Repeat("*", len(runes))
+	}
+	return strings.Repeat("*", len(runes)-4) + string(runes[len(runes)-4:])
+}
+
+// MaskAll replaces the whole value
+func MaskAll(value interface{}) interface{} {
+	return "****"
+}
+
+func maskText(value interface{}) string {
+	if bytes, ok := value.([]byte); ok {
+		return string(bytes)
+	}
+	return fmt.Sprint(value)
+}
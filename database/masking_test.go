@@ -0,0 +1,49 @@
+package database
+
+import (
+	"testing"
+)
+
+func TestMasked(t *testing.T) {
+	defer recovery(t)
+	_, err := tdb.Exec("CREATE TABLE IF NOT EXISTS customers (id INT AUTO_INCREMENT PRIMARY KEY, name VARCHAR(100), email VARCHAR(100), card VARCHAR(20), notes VARCHAR(100))", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	id, err := tdb.MakeRecord(map[string]interface{}{
+		"name":  "Jane",
+		"email": "jane@example.com",
+		"card":  "4242424242424242",
+		"notes": nil,
+	}, "customers").Create()
+	if err != nil {
+		t.Error(err)
+	}
+	support := tdb.Masked(MaskingProfile{
+		"email": MaskEmail,
+		"card":  MaskLast4,
+		"notes": MaskAll,
+	})
+	row, err := support.Row("select name, email, card, notes from customers where id = ?", id)
+	if err != nil {
+		t.Error(err)
+	}
+	expected := map[string]interface{}{
+		"name":  "Jane",
+		"email": "j***@example.com",
+		"card":  "************4242",
+		"notes": "",
+	}
+	for col, value := range expected {
+		if row[col] != value {
+			t.Errorf("expected %s to be %v, got %v", col, value, row[col])
+		}
+	}
+	row, err = tdb.Row("select email from customers where id = ?", id)
+	if err != nil {
+		t.Error(err)
+	}
+	if row["email"] != "jane@example.com" {
+		t.Errorf("expected the original handle not to mask, got %v", row["email"])
+	}
+}
@@ -0,0 +1,140 @@
+package database
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+type modelInfo struct {
+	table      string
+	primaryKey string
+}
+
+type modelRegistry struct {
+	mu    sync.RWMutex
+	types map[reflect.Type]modelInfo
+}
+
+// RegisterModel registers a struct type, given as a value or pointer, as the model of a table with
+// the primary key column (an empty key uses "id"). Fields map to columns by their `db` tag, or by
+// the snake_case form of their name when untagged; fields tagged `db:"-"` are skipped
+func (d *Database) RegisterModel(model interface{}, table string, primaryKey string) error {
+	modelType := reflect.TypeOf(model)
+	if modelType != nil && modelType.Kind() == reflect.Ptr {
+		modelType = modelType.Elem()
+	}
+	if modelType == nil || modelType.Kind() != reflect.Struct {
+		return fmt.Errorf("expected a struct model, got %T", model)
+	}
+	if len(primaryKey) < 1 {
+		primaryKey = defaultKey
+	}
+	d.models.mu.Lock()
+	defer d.models.mu.Unlock()
+	d.models.types[modelType] = modelInfo{table: table, primaryKey: primaryKey}
+	return nil
+}
+
+// Insert inserts a registered model, given as a pointer, setting its primary key from the insert id
+// when it was zero
+func (d *Database) Insert(model interface{}) (int64, error) {
+	value, info, err := d.model(model)
+	if err != nil {
+		return 0, err
+	}
+	properties := modelProperties(value)
+	if key, ok := properties[info.primaryKey]; ok && (key == nil || reflect.ValueOf(key).IsZero()) {
+		delete(properties, info.primaryKey)
+	}
+	id, err := d.MakeRecord(properties, info.table).Create()
+	if err != nil {
+		return 0, err
+	}
+	if _, ok := properties[info.primaryKey]; !ok && id > 0 {
+		if index, ok := structColumns(value.Type())[strings.ToLower(info.primaryKey)]; ok {
+			err = assignValue(value.FieldByIndex(index), info.primaryKey, id)
+		}
+	}
+	return id, err
+}
+
+// Update writes every field of a registered model, given as a pointer, to its row, returning the number of rows changed
+func (d *Database) Update(model interface{}) (int64, error) {
+	value, info, err := d.model(model)
+	if err != nil {
+		return 0, err
+	}
+	properties := modelProperties(value)
+	if _, ok := properties[info.primaryKey]; !ok {
+		return 0, fmt.Errorf("model has no %s field", info.primaryKey)
+	}
+	return d.MakeRecord(properties, info.table).Update(info.primaryKey)
+}
+
+// Find fetches the row with the given primary key into a registered model, given as a pointer
+func (d *Database) Find(model interface{}, id interface{}) error {
+	_, info, err := d.model(model)
+	if err != nil {
+		return err
+	}
+	return d.Get(model,
+		"SELECT * FROM "+quoteIdentifier(d.Name())+"."+quoteIdentifier(info.table)+" WHERE "+quoteIdentifier(info.primaryKey)+" = ? LIMIT 1",
+		id,
+	)
+}
+
+// finds the registration of a model passed as a pointer to a struct
+func (d *Database) model(model interface{}) (reflect.Value, modelInfo, error) {
+	value := reflect.ValueOf(model)
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return value, modelInfo{}, fmt.Errorf("expected a pointer to a struct model, got %T", model)
+	}
+	value = value.Elem()
+	d.models.mu.RLock()
+	info, ok := d.models.types[value.Type()]
+	d.models.mu.RUnlock()
+	if !ok {
+		return value, info, fmt.Errorf("model %s is not registered", value.Type())
+	}
+	return value, info, nil
+}
+
+// maps a struct's fields to Record properties, dereferencing pointers and flattening embedded structs
+func modelProperties(value reflect.Value) map[string]interface{} {
+	properties := make(map[string]interface{})
+	structType := value.Type()
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		tag := strings.Split(field.Tag.Get("db"), ",")[0]
+		if tag == "-" {
+			continue
+		}
+		if field.Anonymous && len(tag) < 1 && field.Type.Kind() == reflect.Struct {
+			for column, property := range modelProperties(value.Field(i)) {
+				if _, ok := properties[column]; !ok {
+					properties[column] = property
+				}
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		column := tag
+		if len(column) < 1 {
+			column = snakeCase(field.Name)
+		}
+		fieldValue := value.Field(i)
+		if fieldValue.Kind() == reflect.Ptr {
+			if fieldValue.IsNil() {
+				properties[column] = nil
+				continue
+			}
+			fieldValue = fieldValue.Elem()
+		}
+		properties[column] = fieldValue.Interface()
+	}
+	return properties
+}
@@ -0,0 +1,72 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type modelWidget struct {
+	ID          int64     `db:"id"`
+	SKU         string    `db:"sku"`
+	Description *string   `db:"description"`
+	Weight      float64   `db:"weight"`
+	CreatedAt   time.Time `db:"-"`
+}
+
+func TestModelProperties(t *testing.T) {
+	description := "Modelled"
+	properties := modelProperties(reflect.ValueOf(struct {
+		modelWidget
+		Notes string
+	}{modelWidget{SKU: "M1", Description: &description}, "fragile"}))
+	expected := map[string]interface{}{
+		"id":          int64(0),
+		"sku":         "M1",
+		"description": "Modelled",
+		"weight":      0.0,
+		"notes":       "fragile",
+	}
+	if !reflect.DeepEqual(properties, expected) {
+		t.Errorf("expected %v, got %v", expected, properties)
+	}
+}
+
+func TestModels(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	d, err := Make(getConfigs(false))
+	if err != nil {
+		t.Error(err)
+	}
+	defer d.Close()
+	if _, err = d.Insert(&modelWidget{SKU: "MODEL1"}); err == nil {
+		t.Errorf("expected an error for a model that is not registered")
+	}
+	err = d.RegisterModel(modelWidget{}, "widgets", "id")
+	if err != nil {
+		t.Error(err)
+	}
+	widget := modelWidget{SKU: "MODEL1", Weight: 3.5}
+	id, err := d.Insert(&widget)
+	if err != nil {
+		t.Error(err)
+	}
+	if widget.ID != id || id < 1 {
+		t.Errorf("expected the model's id to be set to %d, got %d", id, widget.ID)
+	}
+	description := "Modelled Widget"
+	widget.Description = &description
+	_, err = d.Update(&widget)
+	if err != nil {
+		t.Error(err)
+	}
+	var found modelWidget
+	err = d.Find(&found, id)
+	if err != nil {
+		t.Error(err)
+	}
+	if found.SKU != "MODEL1" || found.Weight != 3.5 || found.Description == nil || *found.Description != description {
+		t.Errorf("unexpected model %+v", found)
+	}
+}
@@ -0,0 +1,33 @@
+package database
+
+// Normalizer rewrites text before it is written, such as a Unicode normalization form, so that
+// visually identical strings are stored with the same encoding
+type Normalizer func(string) string
+
+// applies the database's normalizer to string arguments, copying the arguments if any change
+func (d *Database) normalizeArgs(args []interface{}) []interface{} {
+	if d.configs.Normalizer == nil || args == nil {
+		return args
+	}
+	normalized := make([]interface{}, len(args))
+	for i, arg := range args {
+		if text, ok := arg.(string); ok {
+			normalized[i] = d.configs.Normalizer(text)
+			continue
+		}
+		normalized[i] = arg
+	}
+	return normalized
+}
+
+// applies the table's normalizer to a string property
+func (r *Record) normalize(value interface{}) interface{} {
+	normalizer, ok := r.database.configs.TableNormalizers[r.table]
+	if !ok {
+		return value
+	}
+	if text, ok := value.(string); ok {
+		return normalizer(text)
+	}
+	return value
+}
@@ -0,0 +1,45 @@
+package database
+
+import (
+	"strings"
+	"testing"
+)
+
+// composes the combining acute accent, standing in for norm.NFC.String
+var composeAcute = strings.NewReplacer("e\u0301", "\u00e9").Replace
+
+func TestNormalizer(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	configs := getConfigs(false)
+	configs.Normalizer = composeAcute
+	configs.TableNormalizers = map[string]Normalizer{
+		"widgets": strings.TrimSpace,
+	}
+	d, err := Make(configs)
+	if err != nil {
+		t.Error(err)
+	}
+	defer d.Close()
+	id, err := d.MakeRecord(map[string]interface{}{
+		"sku":         "CAFE\u0301",
+		"description": "  Cafe\u0301 Widget  ",
+	}, "widgets").Create()
+	if err != nil {
+		t.Error(err)
+	}
+	row, err := tdb.Row("select sku, description from widgets where id = ?", id)
+	if err != nil {
+		t.Error(err)
+	}
+	if row["description"] != "Caf\u00e9 Widget" {
+		t.Errorf("expected description to be normalized, got %q", row["description"])
+	}
+	rows, err := d.QueryRaw("select id from widgets where description = ?", []interface{}{"Cafe\u0301 Widget"})
+	if err != nil {
+		t.Error(err)
+	}
+	if len(rows) != 1 {
+		t.Errorf("expected the query argument to be normalized, got %d rows", len(rows))
+	}
+}
@@ -0,0 +1,65 @@
+package database
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+)
+
+// IntegerRepresentation is how integer columns appear in query results
+type IntegerRepresentation int
+
+const (
+	// IntegersAsInt64 returns integers as int64, or uint64 for BIGINT UNSIGNED
+	IntegersAsInt64 IntegerRepresentation = iota
+	// IntegersAsInt returns integers as int, leaving values int can't hold as they are
+	IntegersAsInt
+	// IntegersAsJSONNumber returns integers as json.Number
+	IntegersAsJSONNumber
+)
+
+// FloatRepresentation is how FLOAT, DOUBLE and DECIMAL columns appear in query results
+type FloatRepresentation int
+
+const (
+	// FloatsAsFloat64 returns floats as float64
+	FloatsAsFloat64 FloatRepresentation = iota
+	// FloatsAsString returns floats as their shortest decimal string, e.g. "1.5"
+	FloatsAsString
+)
+
+// TypeOptions chooses the representation of numeric values in query results,
+// for serializers that need something other than int64 and float64
+type TypeOptions struct {
+	Integers IntegerRepresentation
+	Floats   FloatRepresentation
+}
+
+// converts a result value to the configured numeric representation
+func (o TypeOptions) convert(value interface{}) interface{} {
+	switch number := value.(type) {
+	case int64:
+		switch o.Integers {
+		case IntegersAsInt:
+			if number >= math.MinInt && number <= math.MaxInt {
+				return int(number)
+			}
+		case IntegersAsJSONNumber:
+			return json.Number(strconv.FormatInt(number, 10))
+		}
+	case uint64:
+		switch o.Integers {
+		case IntegersAsInt:
+			if number <= math.MaxInt {
+				return int(number)
+			}
+		case IntegersAsJSONNumber:
+			return json.Number(strconv.FormatUint(number, 10))
+		}
+	case float64:
+		if o.Floats == FloatsAsString {
+			return strconv.FormatFloat(number, 'f', -1, 64)
+		}
+	}
+	return value
+}
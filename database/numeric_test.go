@@ -0,0 +1,61 @@
+package database
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestTypeOptionsConvert(t *testing.T) {
+	cases := []struct {
+		options  TypeOptions
+		value    interface{}
+		expected interface{}
+	}{
+		{TypeOptions{}, int64(7), int64(7)},
+		{TypeOptions{Integers: IntegersAsInt}, int64(7), 7},
+		{TypeOptions{Integers: IntegersAsInt}, uint64(7), 7},
+		{TypeOptions{Integers: IntegersAsJSONNumber}, int64(-7), json.Number("-7")},
+		{TypeOptions{Integers: IntegersAsJSONNumber}, uint64(18446744073709551615), json.Number("18446744073709551615")},
+		{TypeOptions{Floats: FloatsAsString}, 1.5, "1.5"},
+		{TypeOptions{Floats: FloatsAsString}, 0.1, "0.1"},
+		{TypeOptions{Integers: IntegersAsInt, Floats: FloatsAsString}, "text", "text"},
+	}
+	for _, c := range cases {
+		result := c.options.convert(c.value)
+		if result != c.expected {
+			t.Errorf("expected %v (%T) to convert to %v (%T), got %v (%T)", c.value, c.value, c.expected, c.expected, result, result)
+		}
+	}
+}
+
+func TestTypeOptions(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	d, err := Make(getConfigs(false))
+	if err != nil {
+		t.Error(err)
+	}
+	defer d.Close()
+	d.TypeOptions = TypeOptions{Integers: IntegersAsJSONNumber, Floats: FloatsAsString}
+	rows, err := d.QueryRaw("select count(*) as total, max(weight) as heaviest from widgets", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	if _, ok := rows[0]["total"].(json.Number); !ok {
+		t.Errorf("expected total to be a json.Number, got %T", rows[0]["total"])
+	}
+	if _, ok := rows[0]["heaviest"].(string); !ok {
+		t.Errorf("expected heaviest to be a string, got %T", rows[0]["heaviest"])
+	}
+	d.TypeOptions = TypeOptions{Integers: IntegersAsInt}
+	rows, err = d.QueryRaw("select count(*) as total, max(weight) as heaviest from widgets", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	if _, ok := rows[0]["total"].(int); !ok {
+		t.Errorf("expected total to be an int, got %T", rows[0]["total"])
+	}
+	if _, ok := rows[0]["heaviest"].(float64); !ok {
+		t.Errorf("expected heaviest to be a float64, got %T", rows[0]["heaviest"])
+	}
+}
@@ -0,0 +1,101 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// PreloadRange bounds a range of values on the leading column of an index; nil bounds are open
+type PreloadRange struct {
+	From interface{}
+	To   interface{}
+}
+
+// Preload scans ranges of a table's index (use "PRIMARY" for the primary key) to pull it into the buffer pool.
+// Passing no ranges scans the whole index. It returns the number of index entries read
+func (d *Database) Preload(table string, index string, ranges []PreloadRange) (int64, error) {
+	return d.PreloadWithOptions(table, index, ranges, QueryOptions{})
+}
+
+// PreloadWithOptions preloads ranges of a table's index as Preload does, scanning each range using
+// per-call options, e.g. a Background priority so warming doesn't hold up interactive queries
+func (d *Database) PreloadWithOptions(table string, index string, ranges []PreloadRange, options QueryOptions) (int64, error) {
+	column, err := d.leadingIndexColumn(table, index, options)
+	if err != nil {
+		return 0, err
+	}
+	if len(ranges) < 1 {
+		ranges = []PreloadRange{{}}
+	}
+	var total int64
+	for _, r := range ranges {
+		count, err := d.preloadRange(table, index, column, r, options)
+		if err != nil {
+			return total, err
+		}
+		total += count
+	}
+	return total, nil
+}
+
+func (d *Database) preloadRange(table, index, column string, r PreloadRange, options QueryOptions) (int64, error) {
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s.%s FORCE INDEX (%s)",
+		quoteIdentifier(d.Name()),
+		quoteIdentifier(table),
+		quoteIdentifier(index),
+	)
+	var conditions []string
+	var escaped []interface{}
+	if r.From != nil {
+		conditions = append(conditions, quoteIdentifier(column)+" >= ?")
+		escaped = append(escaped, r.From)
+	}
+	if r.To != nil {
+		conditions = append(conditions, quoteIdentifier(column)+" <= ?")
+		escaped = append(escaped, r.To)
+	}
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	ctx, cancel := options.context()
+	defer cancel()
+	release, err := d.acquireLane(ctx, options.Priority)
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+	connection, done := d.acquire()
+	defer done()
+	var count int64
+	err = connection.QueryRowContext(ctx, traceQuery(ctx, query), d.normalizeArgs(escaped)...).Scan(&count)
+	if err != nil {
+		return 0, traceError(ctx, err)
+	}
+	return count, nil
+}
+
+// finds the first column of an index
+func (d *Database) leadingIndexColumn(table, index string, options QueryOptions) (string, error) {
+	rows, err := d.QueryRawWithOptions(
+		"SELECT column_name AS column_name FROM information_schema.statistics WHERE table_schema = ? AND table_name = ? AND index_name = ? ORDER BY seq_in_index LIMIT 1",
+		[]interface{}{d.Name(), table, index},
+		options,
+	)
+	if err != nil {
+		return "", err
+	}
+	if len(rows) < 1 {
+		return "", fmt.Errorf("index %s not found on table %s", index, table)
+	}
+	column, ok := rows[0]["column_name"].(string)
+	if !ok {
+		return "", errors.New("type-assertion error on index column")
+	}
+	return column, nil
+}
+
+// quotes an identifier with backticks
+func quoteIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
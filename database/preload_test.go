@@ -0,0 +1,46 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestPreload(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	count, err := tdb.Preload("widgets", "PRIMARY", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	if count < 3 {
+		t.Errorf("expected at least 3 entries to be preloaded, got %d", count)
+	}
+	count, err = tdb.Preload("widgets", "PRIMARY", []PreloadRange{
+		{From: 1, To: 2},
+		{From: 3, To: 3},
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	if count != 3 {
+		t.Errorf("expected 3 entries to be preloaded, got %d", count)
+	}
+	count, err = tdb.PreloadWithOptions("widgets", "PRIMARY", []PreloadRange{{From: 1, To: 2}}, QueryOptions{Priority: Background})
+	if err != nil {
+		t.Error(err)
+	}
+	if count != 2 {
+		t.Errorf("expected 2 entries to be preloaded in the background lane, got %d", count)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = tdb.PreloadWithOptions("widgets", "PRIMARY", nil, QueryOptions{Context: ctx})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected a cancelled context to stop the preload, got %v", err)
+	}
+	_, err = tdb.Preload("widgets", "no_such_index", nil)
+	if err == nil {
+		t.Errorf("expected an error for a missing index")
+	}
+}
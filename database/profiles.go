@@ -0,0 +1,72 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+)
+
+// ProfileEnvVar names the environment variable selecting the active profile
+const ProfileEnvVar = "DB_PROFILE"
+
+// BaseProfile is the profile the others inherit unset settings from
+const BaseProfile = "base"
+
+// Profiles holds connection settings per environment, e.g. "dev", "staging" and "prod"
+type Profiles map[string]Configs
+
+// LoadProfiles reads profiles from a JSON file keyed by profile name, with Configs field names as keys
+func LoadProfiles(path string) (Profiles, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	profiles := make(Profiles)
+	err = json.Unmarshal(contents, &profiles)
+	if err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+// Resolve returns the configs of the named profile, or of the profile named by DB_PROFILE when the
+// name is empty. Settings the profile leaves unset are inherited from the base profile; as false is
+// unset, a profile cannot turn off a flag the base profile turns on
+func (p Profiles) Resolve(name string) (*Configs, error) {
+	if len(name) < 1 {
+		name = os.Getenv(ProfileEnvVar)
+	}
+	if len(name) < 1 {
+		return nil, fmt.Errorf("no profile given and %s is not set", ProfileEnvVar)
+	}
+	profile, ok := p[name]
+	if !ok {
+		return nil, fmt.Errorf("profile %s not found", name)
+	}
+	configs := profile
+	if base, ok := p[BaseProfile]; ok && name != BaseProfile {
+		inherit(&configs, base)
+	}
+	return &configs, nil
+}
+
+// Make makes a Database from the resolved profile
+func (p Profiles) Make(name string) (Database, error) {
+	configs, err := p.Resolve(name)
+	if err != nil {
+		return Database{}, err
+	}
+	return Make(configs)
+}
+
+// fills the zero fields of the configs from the base
+func inherit(configs *Configs, base Configs) {
+	fields := reflect.ValueOf(configs).Elem()
+	baseFields := reflect.ValueOf(base)
+	for i := 0; i < fields.NumField(); i++ {
+		if fields.Field(i).IsZero() {
+			fields.Field(i).Set(baseFields.Field(i))
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package database
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestProfilesResolve(t *testing.T) {
+	profiles := Profiles{
+		BaseProfile: {Driver: "mysql", Port: "3306", Username: "app", NullAsNil: true},
+		"dev":       {Host: "localhost", Database: "app_dev"},
+		"prod":      {Host: "db.internal", Database: "app", Port: "3307"},
+	}
+	configs, err := profiles.Resolve("prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if configs.Host != "db.internal" || configs.Port != "3307" || configs.Driver != "mysql" || configs.Username != "app" || !configs.NullAsNil {
+		t.Errorf("unexpected prod configs %+v", configs)
+	}
+	t.Setenv(ProfileEnvVar, "dev")
+	configs, err = profiles.Resolve("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if configs.Database != "app_dev" || configs.Port != "3306" {
+		t.Errorf("unexpected dev configs %+v", configs)
+	}
+	if profiles[BaseProfile].Host != "" {
+		t.Errorf("expected resolving not to change the base profile")
+	}
+	if _, err = profiles.Resolve("staging"); err == nil {
+		t.Errorf("expected an error for a missing profile")
+	}
+}
+
+func TestLoadProfiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.json")
+	err := os.WriteFile(path, []byte(`{"base": {"Driver": "mysql", "TimeZone": "+02:00"}, "staging": {"Host": "staging.internal", "Database": "app"}}`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	profiles, err := LoadProfiles(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	configs, err := profiles.Resolve("staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if configs.Host != "staging.internal" || configs.TimeZone != "+02:00" || configs.Driver != "mysql" {
+		t.Errorf("unexpected staging configs %+v", configs)
+	}
+}
@@ -0,0 +1,256 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// defaultKey is the primary key column Records are found and loaded by
+const defaultKey = "id"
+
+// FindRecord fetches the row of a table with the given id, as a Record that can be changed and updated
+func (d *Database) FindRecord(table string, id interface{}) (*Record, error) {
+	record := d.MakeRecord(map[string]interface{}{defaultKey: id}, table)
+	err := record.Load()
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// FindRecordBy fetches the row of a table matching every one of the given key columns and values,
+// such as a composite primary key
+func (d *Database) FindRecordBy(table string, keys map[string]interface{}) (*Record, error) {
+	record := d.MakeRecord(keys, table)
+	err := record.loadBy(record.fields())
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// Load replaces the Record's properties with the row matching its id
+func (r *Record) Load() error {
+	return r.loadBy([]string{defaultKey})
+}
+
+// Refresh re-reads the record's row by its id, or by the given key columns, replacing its properties
+// with the values in the database, such as those set by defaults or triggers. Save sets the id of new records
+func (r *Record) Refresh(keys ...string) error {
+	if len(keys) < 1 {
+		keys = []string{defaultKey}
+	}
+	return r.loadBy(keys)
+}
+
+func (r *Record) loadBy(keys []string) error {
+	where, values, err := r.keyCondition(keys, "load")
+	if err != nil {
+		return err
+	}
+	where, values, err = r.database.scope(where, values)
+	if err != nil {
+		return err
+	}
+	rows, err := r.database.QueryRawWithOptions(
+		"SELECT * FROM "+quoteIdentifier(r.database.Name())+"."+quoteIdentifier(r.table)+" WHERE "+where+" LIMIT 1",
+		values,
+		r.options(),
+	)
+	if err != nil {
+		return err
+	}
+	if len(rows) < 1 {
+		return ErrNoResult
+	}
+	r.properties = rows[0]
+	if err := r.hydrate(); err != nil {
+		return err
+	}
+	r.markClean()
+	return nil
+}
+
+// Exists reports whether a row matches the record's values for the given columns, or for its id when
+// none are given, e.g. to report a duplicate email before Create fails on a unique index
+func (r *Record) Exists(byColumns ...string) (bool, error) {
+	if len(byColumns) < 1 {
+		byColumns = []string{defaultKey}
+	}
+	where, values, err := r.keyCondition(byColumns, "check")
+	if err != nil {
+		return false, err
+	}
+	where, values, err = r.database.scope(where, values)
+	if err != nil {
+		return false, err
+	}
+	return r.database.ExistsWithOptions(
+		"SELECT 1 FROM "+quoteIdentifier(r.database.Name())+"."+quoteIdentifier(r.table)+" WHERE "+where,
+		values,
+		r.options(),
+	)
+}
+
+// builds a condition matching the record's values for the key columns, erroring if any are missing
+func (r *Record) keyCondition(keys []string, action string) (string, []interface{}, error) {
+	if len(keys) < 1 {
+		return "", nil, fmt.Errorf("no key columns to %s by", action)
+	}
+	if err := r.validateIdentifiers(keys); err != nil {
+		return "", nil, err
+	}
+	conditions := make([]string, 0, len(keys))
+	values := make([]interface{}, 0, len(keys))
+	for _, key := range keys {
+		value, ok := r.properties[key]
+		if !ok || value == nil {
+			return "", nil, fmt.Errorf("record has no %s to %s by", key, action)
+		}
+		bound, err := r.database.bindValue(value)
+		if err != nil {
+			return "", nil, err
+		}
+		conditions = append(conditions, quoteIdentifier(key)+" = ?")
+		values = append(values, bound)
+	}
+	return strings.Join(conditions, " AND "), values, nil
+}
+
+// Save creates the record when it has no id, setting the new id on it and returning it, and updates
+// it when it does, returning the number of rows changed
+func (r *Record) Save() (int64, error) {
+	if r.hasKey() {
+		return r.Update(defaultKey)
+	}
+	delete(r.properties, defaultKey)
+	id, err := r.Create()
+	if err != nil {
+		return 0, err
+	}
+	if _, ok := r.properties[defaultKey]; !ok && id > 0 {
+		r.Set(defaultKey, id)
+		r.original[defaultKey] = id
+	}
+	return id, nil
+}
+
+func (r *Record) hasKey() bool {
+	id, ok := r.properties[defaultKey]
+	return ok && id != nil && !reflect.ValueOf(id).IsZero()
+}
+
+// ErrStaleRecord is returned by Update for a versioned Record when the row's version has moved on
+// since the record was loaded, meaning another writer changed it first
+var ErrStaleRecord = errors.New("record is stale")
+
+// Versioned opts the record into optimistic locking on the version column: Update only writes the
+// row if its version still matches the record's, and increments it. Create starts it at 1
+func (r *Record) Versioned(column string) *Record {
+	r.versionColumn = column
+	return r
+}
+
+// Change is a property's value when the record was loaded and its value now
+type Change struct {
+	From interface{}
+	To   interface{}
+}
+
+// Changes reports the properties changed since the record was loaded, created or last updated.
+// Records that were never written or loaded report every property as changed
+func (r *Record) Changes() map[string]Change {
+	changes := make(map[string]Change)
+	for field, value := range r.properties {
+		if r.dirty(field) {
+			changes[field] = Change{From: r.original[field], To: value}
+		}
+	}
+	return changes
+}
+
+func (r *Record) dirty(field string) bool {
+	if r.original == nil {
+		return true
+	}
+	original, ok := r.original[field]
+	return !ok || !reflect.DeepEqual(original, r.properties[field])
+}
+
+// takes a copy of the properties as they are in the database, so Update only writes what changes
+func (r *Record) markClean() {
+	r.original = make(map[string]interface{}, len(r.properties))
+	for field, value := range r.properties {
+		r.original[field] = cloneValue(value)
+	}
+}
+
+// copies maps and slices, and those nested in them, so a property such as a decoded JSON document
+// that is changed in place still differs from its original
+func cloneValue(value interface{}) interface{} {
+	original := reflect.ValueOf(value)
+	switch original.Kind() {
+	case reflect.Map:
+		if original.IsNil() {
+			return value
+		}
+		cloned := reflect.MakeMapWithSize(original.Type(), original.Len())
+		entries := original.MapRange()
+		for entries.Next() {
+			cloned.SetMapIndex(entries.Key(), cloneElement(entries.Value()))
+		}
+		return cloned.Interface()
+	case reflect.Slice:
+		if original.IsNil() {
+			return value
+		}
+		cloned := reflect.MakeSlice(original.Type(), original.Len(), original.Len())
+		for i := 0; i < original.Len(); i++ {
+			cloned.Index(i).Set(cloneElement(original.Index(i)))
+		}
+		return cloned.Interface()
+	}
+	return value
+}
+
+func cloneElement(element reflect.Value) reflect.Value {
+	cloned := reflect.ValueOf(cloneValue(element.Interface()))
+	if !cloned.IsValid() {
+		return reflect.Zero(element.Type())
+	}
+	return cloned
+}
+
+// the record's property names in sorted order, so generated statements are the same from run to run
+func (r *Record) fields() []string {
+	fields := make([]string, 0, len(r.properties))
+	for field := range r.properties {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// WithContext makes the record's loads and writes use the context, so they stop at a request's
+// deadline or cancellation rather than blocking
+func (r *Record) WithContext(ctx context.Context) *Record {
+	r.ctx = ctx
+	return r
+}
+
+func (r *Record) options() QueryOptions {
+	return QueryOptions{Context: r.ctx}
+}
+
+// Set sets a property of the record
+func (r *Record) Set(field string, value interface{}) *Record {
+	if r.properties == nil {
+		r.properties = make(map[string]interface{})
+	}
+	r.properties[field] = value
+	return r
+}
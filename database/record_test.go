@@ -0,0 +1,332 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFindRecord(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	id, err := tdb.MakeRecord(map[string]interface{}{
+		"sku":         "FIND1",
+		"description": "Found Widget",
+		"weight":      2.5,
+	}, "widgets").Create()
+	if err != nil {
+		t.Error(err)
+	}
+	record, err := tdb.FindRecord("widgets", id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sku, _ := record.Property("sku"); sku != "FIND1" {
+		t.Errorf("expected sku to be FIND1, got %v", sku)
+	}
+	_, err = record.Set("description", "Changed Widget").Update("id")
+	if err != nil {
+		t.Error(err)
+	}
+	reloaded := tdb.MakeRecord(map[string]interface{}{"id": id}, "widgets")
+	err = reloaded.Load()
+	if err != nil {
+		t.Error(err)
+	}
+	if description, _ := reloaded.Property("description"); description != "Changed Widget" {
+		t.Errorf("expected description to be updated, got %v", description)
+	}
+	if weight, _ := reloaded.Property("weight"); weight != 2.5 {
+		t.Errorf("expected weight to be unchanged, got %v", weight)
+	}
+	_, err = tdb.FindRecord("widgets", id+1000)
+	if !errors.Is(err, ErrNoResult) {
+		t.Errorf("expected no result for a missing row, got %v", err)
+	}
+}
+
+func TestSave(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	record := tdb.MakeRecord(map[string]interface{}{
+		"sku":         "SAVE1",
+		"description": "Saved Widget",
+	}, "widgets")
+	id, err := record.Save()
+	if err != nil {
+		t.Error(err)
+	}
+	if saved, _ := record.Property("id"); saved != id {
+		t.Errorf("expected Save to set the new id %d, got %v", id, saved)
+	}
+	_, err = record.Set("description", "Resaved Widget").Save()
+	if err != nil {
+		t.Error(err)
+	}
+	rows, err := tdb.QueryRaw("select description from widgets where sku = ?", []interface{}{"SAVE1"})
+	if err != nil {
+		t.Error(err)
+	}
+	if len(rows) != 1 || rows[0]["description"] != "Resaved Widget" {
+		t.Errorf("expected one updated row, got %v", rows)
+	}
+}
+
+func TestRecordChanges(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	id, err := tdb.MakeRecord(map[string]interface{}{
+		"sku":         "DIRTY1",
+		"description": "Clean Widget",
+		"weight":      1.0,
+	}, "widgets").Create()
+	if err != nil {
+		t.Error(err)
+	}
+	record, err := tdb.FindRecord("widgets", id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(record.Changes()) > 0 {
+		t.Errorf("expected a loaded record to have no changes, got %v", record.Changes())
+	}
+	_, err = tdb.Exec("update widgets set weight = ? where id = ?", []interface{}{9.0, id})
+	if err != nil {
+		t.Error(err)
+	}
+	record.Set("description", "Dirty Widget")
+	changes := record.Changes()
+	if len(changes) != 1 || changes["description"] != (Change{From: "Clean Widget", To: "Dirty Widget"}) {
+		t.Errorf("expected only the description to change, got %v", changes)
+	}
+	_, err = record.Save()
+	if err != nil {
+		t.Error(err)
+	}
+	if len(record.Changes()) > 0 {
+		t.Errorf("expected no changes after saving, got %v", record.Changes())
+	}
+	row, err := tdb.Row("select description, weight from widgets where id = ?", id)
+	if err != nil {
+		t.Error(err)
+	}
+	if row["description"] != "Dirty Widget" || row["weight"] != 9.0 {
+		t.Errorf("expected only the description to be written, got %v", row)
+	}
+}
+
+func TestSaveUnchanged(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	record := tdb.MakeRecord(map[string]interface{}{"sku": "SAVE2"}, "widgets")
+	_, err := record.Save()
+	if err != nil {
+		t.Error(err)
+	}
+	_, err = record.Save()
+	if err != nil {
+		t.Errorf("expected saving an unchanged record to do nothing, got %v", err)
+	}
+}
+
+func TestVersionedRecord(t *testing.T) {
+	defer recovery(t)
+	_, err := tdb.Exec("CREATE TABLE IF NOT EXISTS documents_versioned (id INT AUTO_INCREMENT PRIMARY KEY, title VARCHAR(100), version INT NOT NULL DEFAULT 1)", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	id, err := tdb.MakeRecord(map[string]interface{}{"title": "Draft"}, "documents_versioned").Versioned("version").Create()
+	if err != nil {
+		t.Error(err)
+	}
+	first, err := tdb.FindRecord("documents_versioned", id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := tdb.FindRecord("documents_versioned", id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = first.Versioned("version").Set("title", "First Edit").Save()
+	if err != nil {
+		t.Error(err)
+	}
+	if version, _ := first.GetInt64("version"); version != 2 {
+		t.Errorf("expected the version to be incremented to 2, got %d", version)
+	}
+	_, err = second.Versioned("version").Set("title", "Second Edit").Save()
+	if err != ErrStaleRecord {
+		t.Errorf("expected a stale record error, got %v", err)
+	}
+	row, err := tdb.Row("select title, version from documents_versioned where id = ?", id)
+	if err != nil {
+		t.Error(err)
+	}
+	if row["title"] != "First Edit" || row["version"] != int64(2) {
+		t.Errorf("expected the first edit to win, got %v", row)
+	}
+}
+
+func TestRecordFieldsSorted(t *testing.T) {
+	d := &Database{configs: &Configs{}}
+	record := d.MakeRecord(map[string]interface{}{"sku": "A1", "created_at": nil, "name": "Widget", "id": 1}, "widgets")
+	for i := 0; i < 10; i++ {
+		fields := record.fields()
+		if strings.Join(fields, ",") != "created_at,id,name,sku" {
+			t.Fatalf("expected the fields in sorted order, got %v", fields)
+		}
+	}
+}
+
+func TestKeyCondition(t *testing.T) {
+	d := &Database{configs: &Configs{}}
+	record := d.MakeRecord(map[string]interface{}{"tenant_id": 4, "sku": "A1", "name": "Widget"}, "tenant_widgets")
+	where, values, err := record.keyCondition([]string{"tenant_id", "sku"}, "update")
+	if err != nil {
+		t.Error(err)
+	}
+	if where != "`tenant_id` = ? AND `sku` = ?" || len(values) != 2 || values[0] != 4 || values[1] != "A1" {
+		t.Errorf("unexpected condition %s %v", where, values)
+	}
+	_, _, err = record.keyCondition([]string{"tenant_id", "id"}, "update")
+	if err == nil || err.Error() != "record has no id to update by" {
+		t.Errorf("expected a missing key error, got %v", err)
+	}
+	uuid, err := NewUUID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.configs.UUIDBinary = true
+	_, values, err = d.MakeRecord(map[string]interface{}{"id": uuid}, "tokens").keyCondition([]string{"id"}, "update")
+	if bound, ok := values[0].([]byte); err != nil || !ok || string(bound) != string(uuid.Bytes()) {
+		t.Errorf("expected a UUID key to be bound as 16 bytes, got %v %v", values, err)
+	}
+}
+
+func TestCompositeKeys(t *testing.T) {
+	defer recovery(t)
+	_, err := tdb.Exec("CREATE TABLE IF NOT EXISTS tenant_widgets (tenant_id INT, sku VARCHAR(20), name VARCHAR(100), PRIMARY KEY (tenant_id, sku))", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	for _, tenant := range []int{1, 2} {
+		_, err = tdb.MakeRecord(map[string]interface{}{"tenant_id": tenant, "sku": "COMPOSITE1", "name": "Original"}, "tenant_widgets").Create()
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	record, err := tdb.FindRecordBy("tenant_widgets", map[string]interface{}{"tenant_id": 2, "sku": "COMPOSITE1"})
+	if err != nil {
+		t.Error(err)
+	}
+	affected, err := record.Set("name", "Changed").UpdateBy([]string{"tenant_id", "sku"})
+	if err != nil {
+		t.Error(err)
+	}
+	if affected != 1 {
+		t.Errorf("expected one row to be updated, got %d", affected)
+	}
+	untouched, err := tdb.FindRecordBy("tenant_widgets", map[string]interface{}{"tenant_id": 1, "sku": "COMPOSITE1"})
+	if err != nil {
+		t.Error(err)
+	}
+	if name, _ := untouched.GetString("name"); name != "Original" {
+		t.Errorf("expected the other tenant's row to be left alone, got %s", name)
+	}
+	deleted, err := record.DeleteBy([]string{"tenant_id", "sku"})
+	if err != nil {
+		t.Error(err)
+	}
+	if deleted != 1 {
+		t.Errorf("expected one row to be deleted, got %d", deleted)
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	record := tdb.MakeRecord(map[string]interface{}{"sku": "REFRESH1", "description": "Before"}, "widgets")
+	_, err := record.Save()
+	if err != nil {
+		t.Error(err)
+	}
+	_, err = tdb.Exec("update widgets set description = 'After' where sku = ?", []interface{}{"REFRESH1"})
+	if err != nil {
+		t.Error(err)
+	}
+	err = record.Refresh()
+	if err != nil {
+		t.Error(err)
+	}
+	if description, _ := record.GetString("description"); description != "After" {
+		t.Errorf("expected the description to be refreshed, got %s", description)
+	}
+	if len(record.Changes()) > 0 {
+		t.Errorf("expected a refreshed record to have no changes, got %v", record.Changes())
+	}
+	err = record.Set("description", "Keyed").Refresh("sku")
+	if err != nil {
+		t.Error(err)
+	}
+	if description, _ := record.GetString("description"); description != "After" {
+		t.Errorf("expected refreshing by sku to discard the change, got %s", description)
+	}
+}
+
+func TestRecordExists(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	exists, err := tdb.MakeRecord(map[string]interface{}{"sku": "WIDG1", "weight": 1.0}, "widgets").Exists("sku")
+	if err != nil {
+		t.Error(err)
+	}
+	if !exists {
+		t.Errorf("expected a widget with sku WIDG1 to exist")
+	}
+	exists, err = tdb.MakeRecord(map[string]interface{}{"sku": "WIDG1", "description": "No Such Widget"}, "widgets").Exists("sku", "description")
+	if err != nil {
+		t.Error(err)
+	}
+	if exists {
+		t.Errorf("expected no widget to match both columns")
+	}
+	_, err = tdb.MakeRecord(map[string]interface{}{"sku": "WIDG1"}, "widgets").Exists()
+	if err == nil {
+		t.Errorf("expected a record without an id to fail to check by id")
+	}
+}
+
+func TestQueryOptionsContext(t *testing.T) {
+	parent, cancel := context.WithCancel(context.Background())
+	ctx, release := QueryOptions{Context: parent, Timeout: time.Hour}.context()
+	defer release()
+	cancel()
+	if ctx.Err() != context.Canceled {
+		t.Errorf("expected cancelling the parent to cancel the call, got %v", ctx.Err())
+	}
+	ctx, release = QueryOptions{}.context()
+	defer release()
+	if _, ok := ctx.Deadline(); ok || ctx.Err() != nil {
+		t.Errorf("expected calls without a context or timeout to have no deadline")
+	}
+}
+
+func TestRecordWithContext(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := tdb.MakeRecord(map[string]interface{}{"sku": "CONTEXT1"}, "widgets").WithContext(ctx).Create()
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected a cancelled context to stop the write, got %v", err)
+	}
+	exists, err := tdb.Exists("select id from widgets where sku = ?", []interface{}{"CONTEXT1"})
+	if err != nil {
+		t.Error(err)
+	}
+	if exists {
+		t.Errorf("expected the widget not to be created")
+	}
+}
@@ -0,0 +1,38 @@
+package database
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// MarshalJSON encodes the record's properties as a JSON object
+func (r *Record) MarshalJSON() ([]byte, error) {
+	if r.properties == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(r.properties)
+}
+
+// UnmarshalJSON replaces the record's properties with a JSON object, such as a request body, keeping
+// numbers as json.Number so large integers aren't rounded. Unmarshal into a Record made by MakeRecord
+// so it can be written
+func (r *Record) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var properties map[string]interface{}
+	err := decoder.Decode(&properties)
+	if err != nil {
+		return err
+	}
+	r.properties = properties
+	return nil
+}
+
+// QueryJSON runs a raw select query and encodes the rows as a JSON array of objects
+func (d *Database) QueryJSON(query string, escaped []interface{}) ([]byte, error) {
+	rows, err := d.QueryRaw(query, escaped)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(rows)
+}
@@ -0,0 +1,53 @@
+package database
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRecordJSON(t *testing.T) {
+	d := &Database{configs: &Configs{}}
+	uuid, err := ParseUUID("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	record := d.MakeRecord(map[string]interface{}{"id": uuid, "name": "Jane"}, "users")
+	encoded, err := json.Marshal(record)
+	if err != nil {
+		t.Error(err)
+	}
+	if string(encoded) != `{"id":"6ba7b810-9dad-11d1-80b4-00c04fd430c8","name":"Jane"}` {
+		t.Errorf("unexpected JSON %s", encoded)
+	}
+	input := d.MakeRecord(nil, "users")
+	err = json.Unmarshal([]byte(`{"name": "Joe", "balance": 9007199254740993}`), input)
+	if err != nil {
+		t.Error(err)
+	}
+	if balance, _ := input.GetInt64("balance"); balance != 9007199254740993 {
+		t.Errorf("expected the balance to keep its precision, got %d", balance)
+	}
+	if name, _ := input.GetString("name"); name != "Joe" {
+		t.Errorf("expected name to be Joe, got %s", name)
+	}
+}
+
+func TestQueryJSON(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	encoded, err := tdb.QueryJSON("select sku from widgets where sku = ?", []interface{}{"NOSUCHSKU"})
+	if err != nil {
+		t.Error(err)
+	}
+	if string(encoded) != "[]" {
+		t.Errorf("expected an empty array, got %s", encoded)
+	}
+	encoded, err = tdb.QueryJSON("select sku, weight from widgets limit 1", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	if !strings.HasPrefix(string(encoded), `[{"sku":`) {
+		t.Errorf("unexpected JSON %s", encoded)
+	}
+}
@@ -0,0 +1,172 @@
+package database
+
+import (
+	"sort"
+	"strings"
+)
+
+// maxPlaceholders is the most parameters MySQL accepts in a prepared statement
+const maxPlaceholders = 65535
+
+// Records is a batch of rows for one table, inserted together
+type Records struct {
+	records  []*Record
+	database *Database
+	table    string
+}
+
+// MakeRecords makes a batch of records for a table
+func (d *Database) MakeRecords(properties []map[string]interface{}, table string) *Records {
+	records := &Records{database: d, table: table}
+	for _, row := range properties {
+		records.records = append(records.records, d.MakeRecord(row, table))
+	}
+	return records
+}
+
+// Create inserts the records with multi-row INSERTs, split to stay under max_allowed_packet.
+// Columns missing from a record take their default. It returns the number of rows inserted
+func (r *Records) Create() (int64, error) {
+	if len(r.records) < 1 {
+		return 0, nil
+	}
+	columns, err := r.prepare()
+	if err != nil {
+		return 0, err
+	}
+	maxPacket, err := r.database.maxAllowedPacket()
+	if err != nil {
+		return 0, err
+	}
+	insertStatement := "INSERT INTO " + quoteIdentifier(r.database.Name()) + "." + quoteIdentifier(r.table) +
+		" (" + quoteIdentifiers(columns) + ") VALUES "
+
+	var total int64
+	var rows []string
+	var inserts []interface{}
+	size := len(insertStatement)
+	flush := func() error {
+		if len(rows) < 1 {
+			return nil
+		}
+		result, err := r.database.Exec(insertStatement+strings.Join(rows, ", "), inserts)
+		if err != nil {
+			return err
+		}
+		count, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		total += count
+		rows, inserts, size = nil, nil, len(insertStatement)
+		return nil
+	}
+	for _, record := range r.records {
+		row, values, rowSize, err := record.valuesRow(columns)
+		if err != nil {
+			return total, err
+		}
+		if len(rows) > 0 && (size+rowSize > maxPacket || len(inserts)+len(values) > maxPlaceholders) {
+			if err = flush(); err != nil {
+				return total, err
+			}
+		}
+		rows = append(rows, row)
+		inserts = append(inserts, values...)
+		size += rowSize
+	}
+	err = flush()
+	if err != nil {
+		return total, err
+	}
+	for _, record := range r.records {
+		err = record.runHooks(AfterCreate)
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// validates the records and returns the sorted union of their columns
+func (r *Records) prepare() ([]string, error) {
+	seen := make(map[string]bool)
+	var columns []string
+	for _, record := range r.records {
+		err := record.beforeCreate()
+		if err != nil {
+			return nil, err
+		}
+		for field := range record.properties {
+			if !seen[field] {
+				seen[field] = true
+				columns = append(columns, field)
+			}
+		}
+	}
+	sort.Strings(columns)
+	if len(r.records) > 0 {
+		if err := r.records[0].validateIdentifiers(columns); err != nil {
+			return nil, err
+		}
+	}
+	return columns, nil
+}
+
+// builds the record's row of a multi-row INSERT, with its approximate size on the wire
+func (r *Record) valuesRow(columns []string) (string, []interface{}, int, error) {
+	var placeholders []string
+	var inserts []interface{}
+	size := 0
+	for _, column := range columns {
+		value, ok := r.properties[column]
+		if !ok || r.omitted(value) {
+			placeholders = append(placeholders, "DEFAULT")
+			size += len("DEFAULT, ")
+			continue
+		}
+		placeholder, values, err := r.bind(column, value)
+		if err != nil {
+			return "", nil, 0, err
+		}
+		placeholders = append(placeholders, placeholder)
+		inserts = append(inserts, values...)
+		size += len(placeholder) + 2
+		for _, bound := range values {
+			size += boundSize(bound)
+		}
+	}
+	return "(" + strings.Join(placeholders, ", ") + ")", inserts, size + 4, nil
+}
+
+// approximates the bytes a bound value takes in a statement packet
+func boundSize(value interface{}) int {
+	switch bound := value.(type) {
+	case string:
+		return len(bound) + 9
+	case []byte:
+		return len(bound) + 9
+	default:
+		return 16
+	}
+}
+
+func (d *Database) maxAllowedPacket() (int, error) {
+	var maxPacket int
+	connection, release := d.acquire()
+	defer release()
+	err := connection.QueryRow("SELECT @@max_allowed_packet").Scan(&maxPacket)
+	if err != nil {
+		return 0, err
+	}
+	// leave room for the packet header and the server's own accounting
+	return maxPacket - 1024, nil
+}
+
+func quoteIdentifiers(names []string) string {
+	quoted := make([]string, 0, len(names))
+	for _, name := range names {
+		quoted = append(quoted, quoteIdentifier(name))
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,51 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestMakeRecords(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	var properties []map[string]interface{}
+	for i := 0; i < 2500; i++ {
+		row := map[string]interface{}{
+			"sku":    fmt.Sprintf("BATCH%d", i),
+			"weight": float64(i),
+		}
+		if i%2 == 0 {
+			row["description"] = "Batch Widget"
+		}
+		properties = append(properties, row)
+	}
+	count, err := tdb.MakeRecords(properties, "widgets").Create()
+	if err != nil {
+		t.Error(err)
+	}
+	if count != 2500 {
+		t.Errorf("expected 2500 rows to be inserted, got %d", count)
+	}
+	rows, err := tdb.QueryRaw("select count(*) as total, count(description) as described from widgets where sku like 'BATCH%'", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	if rows[0]["total"] != int64(2500) || rows[0]["described"] != int64(1250) {
+		t.Errorf("unexpected batch contents %v", rows[0])
+	}
+}
+
+func TestRecordsValuesRow(t *testing.T) {
+	d := &Database{configs: &Configs{}}
+	record := d.MakeRecord(map[string]interface{}{"a": 1, "c": "text"}, "table")
+	row, values, size, err := record.valuesRow([]string{"a", "b", "c"})
+	if err != nil {
+		t.Error(err)
+	}
+	if row != "(?, DEFAULT, ?)" {
+		t.Errorf("unexpected row %s", row)
+	}
+	if len(values) != 2 || size < len(row) {
+		t.Errorf("unexpected values %v with size %d", values, size)
+	}
+}
@@ -0,0 +1,51 @@
+package database
+
+import (
+	"fmt"
+)
+
+// HasMany fetches the rows of a child table whose foreign key references this record's id,
+// e.g. user.HasMany("orders", "user_id")
+func (r *Record) HasMany(table, foreignKey string) ([]*Record, error) {
+	id, ok := r.properties[defaultKey]
+	if !ok || id == nil {
+		return nil, fmt.Errorf("record has no %s for %s.%s to reference", defaultKey, table, foreignKey)
+	}
+	where, values, err := r.database.scope(quoteIdentifier(foreignKey)+" = ?", []interface{}{id})
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.database.QueryRawWithOptions(
+		"SELECT * FROM "+quoteIdentifier(r.database.Name())+"."+quoteIdentifier(table)+" WHERE "+where,
+		values,
+		r.options(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return r.database.loadedRecords(table, rows)
+}
+
+// BelongsTo fetches the parent row the record's foreign key references,
+// e.g. order.BelongsTo("users", "user_id")
+func (r *Record) BelongsTo(table, foreignKey string) (*Record, error) {
+	id, ok := r.properties[foreignKey]
+	if !ok || id == nil {
+		return nil, fmt.Errorf("record has no %s to find its %s by", foreignKey, table)
+	}
+	return r.database.FindRecord(table, id)
+}
+
+// makes Records for rows fetched from the table
+func (d *Database) loadedRecords(table string, rows []map[string]interface{}) ([]*Record, error) {
+	records := make([]*Record, 0, len(rows))
+	for _, row := range rows {
+		record := d.MakeRecord(row, table)
+		if err := record.hydrate(); err != nil {
+			return nil, err
+		}
+		record.markClean()
+		records = append(records, record)
+	}
+	return records, nil
+}
@@ -0,0 +1,49 @@
+package database
+
+import (
+	"testing"
+)
+
+func createRelationTables(t *testing.T) {
+	_, err := tdb.Exec("CREATE TABLE IF NOT EXISTS customers_rel (id INT AUTO_INCREMENT PRIMARY KEY, name VARCHAR(100))", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	_, err = tdb.Exec("CREATE TABLE IF NOT EXISTS orders_rel (id INT AUTO_INCREMENT PRIMARY KEY, customer_id INT, total FLOAT)", nil)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRelations(t *testing.T) {
+	defer recovery(t)
+	createRelationTables(t)
+	customer := tdb.MakeRecord(map[string]interface{}{"name": "Related Customer"}, "customers_rel")
+	customerID, err := customer.Save()
+	if err != nil {
+		t.Error(err)
+	}
+	for _, total := range []float64{10, 20} {
+		_, err = tdb.MakeRecord(map[string]interface{}{"customer_id": customerID, "total": total}, "orders_rel").Create()
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	orders, err := customer.HasMany("orders_rel", "customer_id")
+	if err != nil {
+		t.Error(err)
+	}
+	if len(orders) != 2 {
+		t.Fatalf("expected two orders, got %d", len(orders))
+	}
+	owner, err := orders[0].BelongsTo("customers_rel", "customer_id")
+	if err != nil {
+		t.Error(err)
+	}
+	if name, _ := owner.GetString("name"); name != "Related Customer" {
+		t.Errorf("expected the order to belong to Related Customer, got %s", name)
+	}
+	if len(orders[1].Changes()) > 0 {
+		t.Errorf("expected related records to be loaded clean, got %v", orders[1].Changes())
+	}
+}
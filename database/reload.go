@@ -0,0 +1,149 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// connectionPool holds the connection pool shared by a Database and the handles made from it,
+// so Reload can swap it for all of them
+type connectionPool struct {
+	mu sync.RWMutex
+	db *sql.DB
+	// users counts the callers holding db, so a replaced pool is only closed once they are done
+	users *sync.WaitGroup
+	// settings are the configs db was opened with
+	settings *Configs
+}
+
+// swaps in a new pool, returning the old one and its users
+func (p *connectionPool) swap(db *sql.DB, settings *Configs) (*sql.DB, *sync.WaitGroup) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	old, users := p.db, p.users
+	p.db, p.users, p.settings = db, &sync.WaitGroup{}, settings
+	return old, users
+}
+
+// the current pool, without holding it
+func (d *Database) connection() *sql.DB {
+	d.pool.mu.RLock()
+	defer d.pool.mu.RUnlock()
+	return d.pool.db
+}
+
+// acquire returns the current pool and a func to call once done with it, including any rows or
+// transaction it opened, so Reload doesn't close the pool under the caller
+func (d *Database) acquire() (*sql.DB, func()) {
+	d.pool.mu.RLock()
+	defer d.pool.mu.RUnlock()
+	users := d.pool.users
+	users.Add(1)
+	return d.pool.db, users.Done
+}
+
+// the configs the current pool was opened with
+func (d *Database) connectionSettings() *Configs {
+	d.pool.mu.RLock()
+	defer d.pool.mu.RUnlock()
+	return d.pool.settings
+}
+
+// Reload opens a new connection pool with the connection settings of configs (host, port, credentials,
+// time zone, sql_mode and pool limits) and swaps it in for this Database and every handle made from it.
+// The new pool is checked before the swap, so bad settings leave the current pool in use. Reload returns
+// once callers still using the old pool are done with it, then closes it. The cached server version is
+// looked up again on the new pool. Other settings, such as type options, keep their current values,
+// and the database name can't change
+func (d *Database) Reload(configs *Configs) error {
+	next := Database{configs: configs, Schemaless: d.Schemaless}
+	next.setConfigs()
+	if !d.Schemaless && next.configs.Database != d.Name() {
+		return errors.New("reload can't switch to another database")
+	}
+	connection, err := next.open(next.connectionString())
+	if err != nil {
+		return err
+	}
+	err = connection.Ping()
+	if err != nil {
+		connection.Close()
+		return err
+	}
+	old, users := d.pool.swap(connection, configs)
+	if d.server != nil {
+		d.server.mu.Lock()
+		d.server.version = ""
+		d.server.mu.Unlock()
+	}
+	if old == nil {
+		return nil
+	}
+	users.Wait()
+	return old.Close()
+}
+
+// WatchConfigs calls load at the interval, such as a func reading a profiles file or the environment,
+// and reloads the connection when its settings change. Errors from load and Reload go to onError, if
+// set, and the current connection stays in use. It returns a func that stops watching
+func (d *Database) WatchConfigs(load func() (*Configs, error), interval time.Duration, onError func(error)) func() {
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	report := func(err error) {
+		if onError != nil {
+			onError(err)
+		}
+	}
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				configs, err := load()
+				if err != nil {
+					report(err)
+					continue
+				}
+				// fill in what comes from the environment, as Reload does, before comparing
+				loaded := Database{configs: configs, Schemaless: d.Schemaless}
+				loaded.setConfigs()
+				if !connectionSettingsChanged(d.connectionSettings(), configs) {
+					continue
+				}
+				err = d.Reload(configs)
+				if err != nil {
+					report(err)
+				}
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(stop)
+			<-done
+		})
+	}
+}
+
+// reports whether configs differ in the settings Reload applies
+func connectionSettingsChanged(current, next *Configs) bool {
+	return current.Host != next.Host ||
+		current.Port != next.Port ||
+		current.Username != next.Username ||
+		current.Password != next.Password ||
+		current.Driver != next.Driver ||
+		current.TimeZone != next.TimeZone ||
+		current.SQLMode != next.SQLMode ||
+		!reflect.DeepEqual(current.Location, next.Location) ||
+		current.MaxOpenConnections != next.MaxOpenConnections ||
+		current.MaxIdleConnections != next.MaxIdleConnections ||
+		current.ConnectionMaxLifetime != next.ConnectionMaxLifetime
+}
@@ -0,0 +1,194 @@
+package database
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestConnectionSettingsChanged(t *testing.T) {
+	current := &Configs{Host: "127.0.0.1", Port: "3306", Username: "root", Password: "secret"}
+	next := *current
+	if connectionSettingsChanged(current, &next) {
+		t.Errorf("expected identical configs to be unchanged")
+	}
+	next.Password = "rotated"
+	if !connectionSettingsChanged(current, &next) {
+		t.Errorf("expected a new password to be a change")
+	}
+	next = *current
+	next.ConnectionMaxLifetime = time.Minute
+	if !connectionSettingsChanged(current, &next) {
+		t.Errorf("expected new pool limits to be a change")
+	}
+	next = *current
+	next.Fillable = map[string][]string{"users": {"name"}}
+	if connectionSettingsChanged(current, &next) {
+		t.Errorf("expected settings Reload doesn't apply to be ignored")
+	}
+}
+
+func TestConnectionPoolUsers(t *testing.T) {
+	d := &Database{pool: &connectionPool{}}
+	d.pool.swap(nil, &Configs{Host: "old"})
+	_, release := d.acquire()
+	_, users := d.pool.swap(nil, &Configs{Host: "new"})
+	if d.connectionSettings().Host != "new" {
+		t.Errorf("expected the swap to replace the settings")
+	}
+	drained := make(chan struct{})
+	go func() {
+		users.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+		t.Fatalf("expected the old pool to be held until its caller is done")
+	case <-time.After(20 * time.Millisecond):
+	}
+	release()
+	select {
+	case <-drained:
+	case <-time.After(time.Second):
+		t.Errorf("expected the old pool to be released")
+	}
+}
+
+func TestReload(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	d, err := Make(getConfigs(false))
+	if err != nil {
+		t.Error(err)
+	}
+	defer d.Close()
+	handle := d.Masked(nil)
+	if _, err = d.ServerVersion(); err != nil {
+		t.Error(err)
+	}
+	old := d.connection()
+	configs := getConfigs(false)
+	configs.MaxOpenConnections = 2
+	err = d.Reload(configs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.connection() == old {
+		t.Errorf("expected Reload to swap the connection pool")
+	}
+	if len(d.server.version) > 0 {
+		t.Errorf("expected Reload to clear the cached server version")
+	}
+	if handle.connection() != d.connection() {
+		t.Errorf("expected handles to share the reloaded pool")
+	}
+	if stats := d.connection().Stats(); stats.MaxOpenConnections != 2 {
+		t.Errorf("expected the pool to be capped at 2 connections, got %d", stats.MaxOpenConnections)
+	}
+	if _, err = handle.QueryRaw("SELECT COUNT(*) FROM widgets", nil); err != nil {
+		t.Error(err)
+	}
+	configs = getConfigs(false)
+	configs.Password = "not the password"
+	current := d.connection()
+	if err = d.Reload(configs); err == nil {
+		t.Errorf("expected Reload to reject bad credentials")
+	}
+	if d.connection() != current {
+		t.Errorf("expected a failed Reload to keep the current pool")
+	}
+	held, release := d.acquire()
+	reloaded := make(chan error)
+	go func() {
+		reloaded <- d.Reload(getConfigs(false))
+	}()
+	time.Sleep(100 * time.Millisecond)
+	if _, err = held.Exec("SELECT 1"); err != nil {
+		t.Errorf("expected a held pool to stay open during a Reload, got %v", err)
+	}
+	release()
+	if err = <-reloaded; err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWatchConfigs(t *testing.T) {
+	defer recovery(t)
+	d, err := Make(getConfigs(false))
+	if err != nil {
+		t.Error(err)
+	}
+	defer d.Close()
+	failed := errors.New("profile unreadable")
+	loads := 0
+	errs := make(chan error, 10)
+	stop := d.WatchConfigs(func() (*Configs, error) {
+		loads++
+		if loads == 1 {
+			return nil, failed
+		}
+		configs := getConfigs(false)
+		configs.MaxOpenConnections = 3
+		return configs, nil
+	}, 10*time.Millisecond, func(err error) {
+		errs <- err
+	})
+	deadline := time.Now().Add(5 * time.Second)
+	for d.connection().Stats().MaxOpenConnections != 3 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	reloaded := d.connection()
+	time.Sleep(50 * time.Millisecond)
+	stop()
+	stop()
+	if reloaded.Stats().MaxOpenConnections != 3 {
+		t.Fatalf("expected the watched configs to be applied")
+	}
+	if d.connection() != reloaded {
+		t.Errorf("expected unchanged configs not to reload again")
+	}
+	if d.connectionSettings().MaxOpenConnections != 3 {
+		t.Errorf("expected the reloaded configs to become the current settings")
+	}
+	select {
+	case err = <-errs:
+		if err != failed {
+			t.Errorf("expected the load error to be reported, got %v", err)
+		}
+	default:
+		t.Errorf("expected the load error to be reported")
+	}
+}
+
+func TestWatchConfigsFromEnv(t *testing.T) {
+	defer recovery(t)
+	configs := getConfigs(false)
+	t.Setenv("DB_HOST", configs.Host)
+	t.Setenv("DB_PORT", configs.Port)
+	d, err := Make(getConfigs(false))
+	if err != nil {
+		t.Error(err)
+	}
+	defer d.Close()
+	stop := d.WatchConfigs(func() (*Configs, error) {
+		configs := getConfigs(false)
+		configs.Host, configs.Port = "", ""
+		configs.MaxOpenConnections = 4
+		return configs, nil
+	}, 10*time.Millisecond, func(err error) {
+		t.Errorf("unexpected watch error %v", err)
+	})
+	deadline := time.Now().Add(5 * time.Second)
+	for d.connection().Stats().MaxOpenConnections != 4 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	reloaded := d.connection()
+	time.Sleep(50 * time.Millisecond)
+	stop()
+	if reloaded.Stats().MaxOpenConnections != 4 {
+		t.Fatalf("expected the watched configs to be applied")
+	}
+	if d.connection() != reloaded {
+		t.Errorf("expected configs filled from the environment not to reload on every tick")
+	}
+}
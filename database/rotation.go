@@ -0,0 +1,191 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RotationPeriod is how long each of a rotated table's tables covers
+type RotationPeriod int
+
+const (
+	// RotateMonthly suffixes tables with the month, e.g. logs_202401
+	RotateMonthly RotationPeriod = iota
+	// RotateDaily suffixes tables with the day, e.g. logs_20240131
+	RotateDaily
+)
+
+// RotationOptions configures a RotatedTable
+type RotationOptions struct {
+	Period RotationPeriod
+	// Retention is the number of periods kept, including the current one; zero keeps every table
+	Retention int
+	// ArchiveSchema, when set, is where expired tables are moved rather than dropped
+	ArchiveSchema string
+}
+
+// RotatedTable writes log-style data to period-suffixed copies of a template table,
+// as a lighter alternative to partitioning
+type RotatedTable struct {
+	database *Database
+	base     string
+	options  RotationOptions
+}
+
+// RotatedTable makes a rotated table whose period tables are created like the template table base
+func (d *Database) RotatedTable(base string, options RotationOptions) *RotatedTable {
+	return &RotatedTable{database: d, base: base, options: options}
+}
+
+func (t *RotatedTable) layout() string {
+	if t.options.Period == RotateDaily {
+		return "20060102"
+	}
+	return "200601"
+}
+
+func (t *RotatedTable) periodStart(at time.Time) time.Time {
+	if t.options.Period == RotateDaily {
+		return time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, at.Location())
+	}
+	return time.Date(at.Year(), at.Month(), 1, 0, 0, 0, 0, at.Location())
+}
+
+// offsets a period start by a number of periods
+func (t *RotatedTable) shift(start time.Time, periods int) time.Time {
+	if t.options.Period == RotateDaily {
+		return start.AddDate(0, 0, periods)
+	}
+	return start.AddDate(0, periods, 0)
+}
+
+// TableFor returns the name of the table covering the time
+func (t *RotatedTable) TableFor(at time.Time) string {
+	return t.base + "_" + at.Format(t.layout())
+}
+
+// Prepare creates the tables for the period covering the time and the one after it,
+// so writes never wait on a table being created at the turn of a period
+func (t *RotatedTable) Prepare(at time.Time) error {
+	start := t.periodStart(at)
+	for _, period := range []time.Time{start, t.shift(start, 1)} {
+		_, err := t.database.Exec(fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s.%s LIKE %s.%s",
+			quoteIdentifier(t.database.Name()),
+			quoteIdentifier(t.TableFor(period)),
+			quoteIdentifier(t.database.Name()),
+			quoteIdentifier(t.base),
+		), nil)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MakeRecord makes a record for the table of the current period
+func (t *RotatedTable) MakeRecord(properties map[string]interface{}) *Record {
+	return t.database.MakeRecord(properties, t.TableFor(time.Now()))
+}
+
+// Recent returns a derived table unioning the tables of the given number of periods up to the time,
+// for use in a FROM clause, e.g. "SELECT * FROM " + recent + " WHERE level = ?". Periods without
+// a table are left out
+func (t *RotatedTable) Recent(at time.Time, periods int) (string, error) {
+	tables, err := t.tables(at.Location())
+	if err != nil {
+		return "", err
+	}
+	existing := make(map[string]bool)
+	for _, table := range tables {
+		existing[table.name] = true
+	}
+	var selects []string
+	start := t.periodStart(at)
+	for i := 0; i < periods; i++ {
+		name := t.TableFor(t.shift(start, -i))
+		if existing[name] {
+			selects = append(selects, "SELECT * FROM "+quoteIdentifier(t.database.Name())+"."+quoteIdentifier(name))
+		}
+	}
+	if len(selects) < 1 {
+		return "", fmt.Errorf("no %s tables for the last %d periods", t.base, periods)
+	}
+	return "(" + strings.Join(selects, " UNION ALL ") + ") AS " + quoteIdentifier(t.base), nil
+}
+
+// Expire drops the tables of periods older than the retention, or moves them to the archive schema.
+// It returns the names of the expired tables
+func (t *RotatedTable) Expire(at time.Time) ([]string, error) {
+	if t.options.Retention < 1 {
+		return nil, errors.New("rotated table has no retention to expire tables by")
+	}
+	tables, err := t.tables(at.Location())
+	if err != nil {
+		return nil, err
+	}
+	cutoff := t.shift(t.periodStart(at), 1-t.options.Retention)
+	var expired []string
+	for _, table := range tables {
+		if !table.start.Before(cutoff) {
+			continue
+		}
+		var statement string
+		if len(t.options.ArchiveSchema) > 0 {
+			statement = fmt.Sprintf("RENAME TABLE %s.%s TO %s.%s",
+				quoteIdentifier(t.database.Name()), quoteIdentifier(table.name),
+				quoteIdentifier(t.options.ArchiveSchema), quoteIdentifier(table.name))
+		} else {
+			statement = fmt.Sprintf("DROP TABLE %s.%s", quoteIdentifier(t.database.Name()), quoteIdentifier(table.name))
+		}
+		_, err = t.database.Exec(statement, nil)
+		if err != nil {
+			return expired, err
+		}
+		expired = append(expired, table.name)
+	}
+	return expired, nil
+}
+
+type periodTable struct {
+	name  string
+	start time.Time
+}
+
+// lists the existing period tables, oldest first
+func (t *RotatedTable) tables(location *time.Location) ([]periodTable, error) {
+	rows, err := t.database.QueryRaw(
+		"SELECT table_name AS table_name FROM information_schema.tables WHERE table_schema = ? AND table_name LIKE ?",
+		[]interface{}{t.database.Name(), EscapeLike(t.base+"_") + "%"},
+	)
+	if err != nil {
+		return nil, err
+	}
+	var tables []periodTable
+	for _, row := range rows {
+		name, _ := row["table_name"].(string)
+		start, err := t.parsePeriod(strings.TrimPrefix(name, t.base+"_"), location)
+		if err != nil {
+			continue
+		}
+		tables = append(tables, periodTable{name: name, start: start})
+	}
+	sort.Slice(tables, func(i, j int) bool {
+		return tables[i].start.Before(tables[j].start)
+	})
+	return tables, nil
+}
+
+// parses a table suffix, rejecting suffixes that aren't exactly a period of this table
+func (t *RotatedTable) parsePeriod(suffix string, location *time.Location) (time.Time, error) {
+	start, err := time.ParseInLocation(t.layout(), suffix, location)
+	if err != nil {
+		return start, err
+	}
+	if start.Format(t.layout()) != suffix {
+		return start, fmt.Errorf("%s is not a period suffix", suffix)
+	}
+	return start, nil
+}
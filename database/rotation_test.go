@@ -0,0 +1,74 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRotatedTableNames(t *testing.T) {
+	d := &Database{}
+	at := time.Date(2024, time.January, 31, 12, 0, 0, 0, time.UTC)
+	monthly := d.RotatedTable("logs", RotationOptions{})
+	if monthly.TableFor(at) != "logs_202401" {
+		t.Errorf("unexpected monthly table %s", monthly.TableFor(at))
+	}
+	if next := monthly.TableFor(monthly.shift(monthly.periodStart(at), 1)); next != "logs_202402" {
+		t.Errorf("expected the next month to be logs_202402, got %s", next)
+	}
+	daily := d.RotatedTable("logs", RotationOptions{Period: RotateDaily})
+	if next := daily.TableFor(daily.shift(daily.periodStart(at), 1)); next != "logs_20240201" {
+		t.Errorf("expected the next day to be logs_20240201, got %s", next)
+	}
+	if _, err := monthly.parsePeriod("20240131", time.UTC); err == nil {
+		t.Errorf("expected a daily suffix not to parse as a month")
+	}
+}
+
+func TestRotatedTable(t *testing.T) {
+	defer recovery(t)
+	_, err := tdb.Exec("CREATE TABLE IF NOT EXISTS events (id INT AUTO_INCREMENT PRIMARY KEY, level VARCHAR(10))", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	events := tdb.RotatedTable("events", RotationOptions{Retention: 2})
+	months := []time.Time{
+		time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, time.February, 15, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC),
+	}
+	for _, month := range months {
+		if err = events.Prepare(month); err != nil {
+			t.Error(err)
+		}
+		_, err = tdb.MakeRecord(map[string]interface{}{"level": "info"}, events.TableFor(month)).Create()
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	hasNext, err := tdb.CheckHasTable("events_202404")
+	if err != nil {
+		t.Error(err)
+	}
+	if !hasNext {
+		t.Errorf("expected the next period's table to be created ahead of time")
+	}
+	recent, err := events.Recent(months[2], 2)
+	if err != nil {
+		t.Error(err)
+	}
+	rows, err := tdb.QueryRaw("select count(*) as total from "+recent+" where level = ?", []interface{}{"info"})
+	if err != nil {
+		t.Error(err)
+	}
+	if rows[0]["total"] != int64(2) {
+		t.Errorf("expected two rows across the last two periods, got %v", rows[0]["total"])
+	}
+	expired, err := events.Expire(months[2])
+	if err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual(expired, []string{"events_202401"}) {
+		t.Errorf("expected January to expire, got %v", expired)
+	}
+}
@@ -0,0 +1,272 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Query runs a select query and scans each row into a T. Struct fields are matched to columns by
+// their `db` tag, or by name (or its snake_case form) when untagged; fields tagged `db:"-"` are
+// skipped. T may also be a pointer to a struct. Other types are scanned from single-column results
+func Query[T any](d *Database, query string, escaped []interface{}) ([]T, error) {
+	rows, err := d.QueryRaw(query, escaped)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]T, 0, len(rows))
+	for _, row := range rows {
+		var item T
+		err = scanRow(row, reflect.ValueOf(&item).Elem())
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+// First runs a select query and scans the first row into a T
+func First[T any](d *Database, query string, escaped []interface{}) (T, error) {
+	var item T
+	rows, err := Query[T](d, query, escaped)
+	if err != nil {
+		return item, err
+	}
+	if len(rows) < 1 {
+		return item, ErrNoResult
+	}
+	return rows[0], nil
+}
+
+// Select runs a select query and scans the rows into dest, a pointer to a slice of structs,
+// struct pointers or single-column values, matching columns to fields as Query does
+func (d *Database) Select(dest interface{}, query string, args ...interface{}) error {
+	slice := reflect.ValueOf(dest)
+	if slice.Kind() != reflect.Ptr || slice.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("expected a pointer to a slice, got %T", dest)
+	}
+	slice = slice.Elem()
+	rows, err := d.QueryRaw(query, args)
+	if err != nil {
+		return err
+	}
+	elemType := slice.Type().Elem()
+	result := reflect.MakeSlice(slice.Type(), 0, len(rows))
+	for _, row := range rows {
+		item := reflect.New(elemType).Elem()
+		target := item
+		if elemType.Kind() == reflect.Ptr {
+			item = reflect.New(elemType.Elem())
+			target = item.Elem()
+		}
+		err = scanRow(row, target)
+		if err != nil {
+			return err
+		}
+		result = reflect.Append(result, item)
+	}
+	slice.Set(result)
+	return nil
+}
+
+// Get runs a select query and scans the first row into dest, a pointer to a struct or value
+func (d *Database) Get(dest interface{}, query string, args ...interface{}) error {
+	target := reflect.ValueOf(dest)
+	if target.Kind() != reflect.Ptr || target.IsNil() {
+		return fmt.Errorf("expected a non-nil pointer, got %T", dest)
+	}
+	rows, err := d.QueryRaw(query, args)
+	if err != nil {
+		return err
+	}
+	if len(rows) < 1 {
+		return ErrNoResult
+	}
+	return scanRow(rows[0], target.Elem())
+}
+
+// scans a result row into a struct or struct pointer, or a single-column row into any other type
+func scanRow(row map[string]interface{}, dest reflect.Value) error {
+	if dest.Kind() == reflect.Ptr && !isScalar(dest.Type().Elem()) {
+		item := reflect.New(dest.Type().Elem())
+		err := scanRow(row, item.Elem())
+		if err != nil {
+			return err
+		}
+		dest.Set(item)
+		return nil
+	}
+	if isScalar(dest.Type()) {
+		if len(row) != 1 {
+			return fmt.Errorf("cannot scan %d columns into %s", len(row), dest.Type())
+		}
+		for col, value := range row {
+			return assignValue(dest, col, value)
+		}
+	}
+	fields := structColumns(dest.Type())
+	for col, value := range row {
+		index, ok := fields[strings.ToLower(col)]
+		if !ok {
+			continue
+		}
+		err := assignValue(dest.FieldByIndex(index), col, value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// maps lower-cased column names to struct fields, including fields of embedded structs
+func structColumns(structType reflect.Type) map[string][]int {
+	fields := make(map[string][]int)
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		tag := strings.Split(field.Tag.Get("db"), ",")[0]
+		if tag == "-" {
+			continue
+		}
+		if field.Anonymous && len(tag) < 1 && field.Type.Kind() == reflect.Struct {
+			for col, index := range structColumns(field.Type) {
+				if _, ok := fields[col]; !ok {
+					fields[col] = append([]int{i}, index...)
+				}
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		for _, col := range fieldColumns(field.Name, tag) {
+			fields[strings.ToLower(col)] = []int{i}
+		}
+	}
+	return fields
+}
+
+// the column names a field can be matched to
+func fieldColumns(name string, tag string) []string {
+	if len(tag) > 0 {
+		return []string{tag}
+	}
+	return []string{name, snakeCase(name)}
+}
+
+// converts a field name such as UserID or CreatedAt to user_id or created_at
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var builder strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			previousLower := unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if previousLower || (unicode.IsUpper(runes[i-1]) && nextLower) {
+				builder.WriteRune('_')
+			}
+		}
+		builder.WriteRune(unicode.ToLower(r))
+	}
+	return builder.String()
+}
+
+var scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// whether a type is scanned from a single column rather than mapped field by field
+func isScalar(t reflect.Type) bool {
+	return t.Kind() != reflect.Struct || t == timeType || reflect.PtrTo(t).Implements(scannerType)
+}
+
+// assigns a result value to a field, converting between compatible types
+func assignValue(field reflect.Value, col string, value interface{}) error {
+	if value == nil {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+	if scanner, ok := field.Addr().Interface().(sql.Scanner); ok {
+		return scanner.Scan(value)
+	}
+	if field.Kind() == reflect.Ptr {
+		elem := reflect.New(field.Type().Elem())
+		err := assignValue(elem.Elem(), col, value)
+		if err != nil {
+			return err
+		}
+		field.Set(elem)
+		return nil
+	}
+	source := reflect.ValueOf(value)
+	if source.Type().AssignableTo(field.Type()) {
+		field.Set(source)
+		return nil
+	}
+	err := convertValue(field, source)
+	if err != nil {
+		return fmt.Errorf("column %s: %w", col, err)
+	}
+	return nil
+}
+
+func convertValue(field reflect.Value, source reflect.Value) error {
+	if source.Kind() == reflect.String && isNumericKind(field.Kind()) {
+		return parseNumber(field, source.String())
+	}
+	if isNumericKind(source.Kind()) && isNumericKind(field.Kind()) {
+		field.Set(source.Convert(field.Type()))
+		return nil
+	}
+	if field.Kind() == reflect.String && (source.Kind() == reflect.String || source.Type() == reflect.TypeOf([]byte{})) {
+		field.SetString(source.Convert(reflect.TypeOf("")).String())
+		return nil
+	}
+	if field.Kind() == reflect.Bool && isNumericKind(source.Kind()) {
+		field.SetBool(!source.IsZero())
+		return nil
+	}
+	if source.Type().ConvertibleTo(field.Type()) && source.Kind() == field.Kind() {
+		field.Set(source.Convert(field.Type()))
+		return nil
+	}
+	return fmt.Errorf("cannot assign %s to %s", source.Type(), field.Type())
+}
+
+func parseNumber(field reflect.Value, value string) error {
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(parsed)
+	default:
+		parsed, err := strconv.ParseFloat(value, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(parsed)
+	}
+	return nil
+}
+
+func isNumericKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
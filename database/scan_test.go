@@ -0,0 +1,131 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+type widget struct {
+	ID          int64     `db:"id"`
+	SKU         string    `db:"sku"`
+	Description *string   `db:"description"`
+	Weight      float32   `db:"weight"`
+	CreatedAt   time.Time `db:"created_at"`
+	Ignored     string    `db:"-"`
+}
+
+func TestQuery(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	widgets, err := Query[widget](tdb, "select * from widgets where sku in (?, ?) order by id", []interface{}{"WIDG1", "WIDG2"})
+	if err != nil {
+		t.Error(err)
+	}
+	if len(widgets) != 2 {
+		t.Fatalf("expected 2 widgets, got %d", len(widgets))
+	}
+	if widgets[0].ID != 1 || widgets[0].SKU != "WIDG1" {
+		t.Errorf("expected the first widget to be WIDG1 with id 1, got %+v", widgets[0])
+	}
+	if widgets[0].Description == nil || *widgets[0].Description != "Widget One" {
+		t.Errorf("expected the first widget's description to be 'Widget One', got %v", widgets[0].Description)
+	}
+	if widgets[0].CreatedAt.IsZero() {
+		t.Errorf("expected created_at to be scanned")
+	}
+	pointers, err := Query[*widget](tdb, "select * from widgets where sku = ?", []interface{}{"WIDG2"})
+	if err != nil {
+		t.Error(err)
+	}
+	if len(pointers) != 1 || pointers[0] == nil || pointers[0].SKU != "WIDG2" {
+		t.Errorf("expected a pointer to WIDG2, got %v", pointers)
+	}
+	skus, err := Query[string](tdb, "select sku from widgets where sku in (?, ?) order by id", []interface{}{"WIDG1", "WIDG2"})
+	if err != nil {
+		t.Error(err)
+	}
+	if len(skus) != 2 || skus[1] != "WIDG2" {
+		t.Errorf("expected skus WIDG1 and WIDG2, got %v", skus)
+	}
+}
+
+func TestFirst(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	found, err := First[widget](tdb, "select * from widgets where sku = ?", []interface{}{"WIDG3"})
+	if err != nil {
+		t.Error(err)
+	}
+	if found.SKU != "WIDG3" {
+		t.Errorf("expected WIDG3, got %s", found.SKU)
+	}
+	_, err = First[widget](tdb, "select * from widgets where sku = ?", []interface{}{"WIDG-MISSING"})
+	if err == nil {
+		t.Errorf("expected an error when there is no result")
+	}
+}
+
+type untaggedWidget struct {
+	ID          int64
+	SKU         string
+	Description string
+	CreatedAt   time.Time
+}
+
+func TestSelect(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	var widgets []*untaggedWidget
+	err := tdb.Select(&widgets, "select id, sku, description, created_at from widgets where sku in (?, ?) order by id", "WIDG1", "WIDG2")
+	if err != nil {
+		t.Error(err)
+	}
+	if len(widgets) != 2 {
+		t.Fatalf("expected 2 widgets, got %d", len(widgets))
+	}
+	if widgets[1].SKU != "WIDG2" || widgets[1].Description != "Widget Two" {
+		t.Errorf("expected the second widget to be WIDG2, got %+v", widgets[1])
+	}
+	if widgets[1].CreatedAt.IsZero() {
+		t.Errorf("expected created_at to be matched to CreatedAt")
+	}
+	err = tdb.Select(widgets, "select id from widgets")
+	if err == nil {
+		t.Errorf("expected an error when dest is not a pointer to a slice")
+	}
+}
+
+func TestGet(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	var found untaggedWidget
+	err := tdb.Get(&found, "select id, sku from widgets where sku = ?", "WIDG3")
+	if err != nil {
+		t.Error(err)
+	}
+	if found.SKU != "WIDG3" || found.ID < 1 {
+		t.Errorf("expected WIDG3, got %+v", found)
+	}
+	var count int
+	err = tdb.Get(&count, "select count(*) from widgets where sku like ?", "WIDG%")
+	if err != nil {
+		t.Error(err)
+	}
+	if count < 3 {
+		t.Errorf("expected at least 3 widgets, got %d", count)
+	}
+}
+
+func TestSnakeCase(t *testing.T) {
+	for name, expected := range map[string]string{
+		"ID":        "id",
+		"UserID":    "user_id",
+		"CreatedAt": "created_at",
+		"HTTPCode":  "http_code",
+		"Address2":  "address2",
+	} {
+		if snakeCase(name) != expected {
+			t.Errorf("expected %s to be %s, got %s", name, expected, snakeCase(name))
+		}
+	}
+}
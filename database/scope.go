@@ -0,0 +1,55 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// scopeRegistry holds the named conditions registered with RegisterScope
+type scopeRegistry struct {
+	mu     sync.RWMutex
+	scopes map[string]scope
+}
+
+type scope struct {
+	condition string
+	args      []interface{}
+}
+
+// RegisterScope names a reusable condition, e.g. RegisterScope("active", "deleted_at IS NULL AND active = ?", 1),
+// for Scoped to apply. The condition is SQL written against the columns of the tables it is used with
+func (d *Database) RegisterScope(name, condition string, args ...interface{}) {
+	d.scopes.mu.Lock()
+	defer d.scopes.mu.Unlock()
+	d.scopes.scopes[name] = scope{condition: condition, args: args}
+}
+
+// Scoped returns a handle on the same connection whose Record fetches, such as FindRecord, FindRecordBy,
+// Refresh and HasMany, only match rows that satisfy the named scopes as well. Closing either handle
+// closes the shared connection
+func (d *Database) Scoped(names ...string) *Database {
+	scoped := *d
+	scoped.applied = append(append([]string{}, d.applied...), names...)
+	return &scoped
+}
+
+// adds the handle's scopes to a fetch's condition
+func (d *Database) scope(condition string, values []interface{}) (string, []interface{}, error) {
+	if len(d.applied) < 1 {
+		return condition, values, nil
+	}
+	d.scopes.mu.RLock()
+	defer d.scopes.mu.RUnlock()
+	conditions := []string{"(" + condition + ")"}
+	scopedValues := append([]interface{}{}, values...)
+	for _, name := range d.applied {
+		registered, ok := d.scopes.scopes[name]
+		if !ok {
+			return "", nil, fmt.Errorf("scope %s is not registered", name)
+		}
+		conditions = append(conditions, "("+registered.condition+")")
+		scopedValues = append(scopedValues, registered.args...)
+	}
+	return strings.Join(conditions, " AND "), scopedValues, nil
+}
@@ -0,0 +1,53 @@
+package database
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestScope(t *testing.T) {
+	d := &Database{configs: &Configs{}, scopes: &scopeRegistry{scopes: make(map[string]scope)}}
+	d.RegisterScope("active", "deleted_at IS NULL AND active = ?", 1)
+	d.RegisterScope("recent", "created_at > NOW() - INTERVAL 1 DAY")
+	condition, values, err := d.scope("`id` = ?", []interface{}{5})
+	if err != nil || condition != "`id` = ?" || len(values) != 1 {
+		t.Errorf("expected an unscoped handle to leave the condition alone, got %s %v %v", condition, values, err)
+	}
+	scoped := d.Scoped("active").Scoped("recent")
+	condition, values, err = scoped.scope("`id` = ?", []interface{}{5})
+	if err != nil {
+		t.Error(err)
+	}
+	if condition != "(`id` = ?) AND (deleted_at IS NULL AND active = ?) AND (created_at > NOW() - INTERVAL 1 DAY)" {
+		t.Errorf("unexpected condition %s", condition)
+	}
+	if len(values) != 2 || values[0] != 5 || values[1] != 1 {
+		t.Errorf("unexpected values %v", values)
+	}
+	if len(d.applied) > 0 {
+		t.Errorf("expected scoping a handle to leave the original alone")
+	}
+	if _, _, err = d.Scoped("missing").scope("`id` = ?", nil); err == nil {
+		t.Errorf("expected an unregistered scope to fail")
+	}
+}
+
+func TestScopedFind(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	tdb.RegisterScope("light", "weight < ?", 1.0)
+	record := tdb.MakeRecord(map[string]interface{}{"sku": "SCOPED1", "weight": 50.0}, "widgets")
+	_, err := record.Save()
+	if err != nil {
+		t.Error(err)
+	}
+	id, _ := record.Property("id")
+	_, err = tdb.Scoped("light").FindRecord("widgets", id)
+	if !errors.Is(err, ErrNoResult) {
+		t.Errorf("expected the scope to exclude the widget, got %v", err)
+	}
+	_, err = tdb.FindRecord("widgets", id)
+	if err != nil {
+		t.Errorf("expected the unscoped find to return the widget, got %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// ScriptVar refers to a variable captured by an earlier ScriptStep, for use in a step's Args
+type ScriptVar string
+
+// ScriptStep is a statement run by RunScript
+type ScriptStep struct {
+	Query string
+	Args  []interface{}
+	// CaptureInsertID stores the statement's LAST_INSERT_ID under this variable name
+	CaptureInsertID string
+	// Capture maps columns of the first row the query returns to variable names.
+	// A step that captures columns is run as a query, so it must return a row
+	Capture map[string]string
+}
+
+// RunScript runs the steps in one transaction, passing captured variables to later steps through
+// ScriptVar args, e.g. capturing a parent's insert id for its children. Any failure rolls back the
+// whole script. It returns the captured variables. On a DualWrite handle the script's statements are
+// mirrored once it commits; steps that capture columns are run as queries and aren't mirrored
+func (d *Database) RunScript(steps []ScriptStep) (map[string]interface{}, error) {
+	for attempt := 0; ; attempt++ {
+		variables, err := d.runScript(steps)
+		if err == nil || attempt >= d.writeConflictRetries() || !isWriteConflict(err) {
+			return variables, err
+		}
+	}
+}
+
+// a statement a script ran, kept to be mirrored once the script commits
+type scriptWrite struct {
+	query   string
+	inserts []interface{}
+	result  sql.Result
+}
+
+func (d *Database) runScript(steps []ScriptStep) (map[string]interface{}, error) {
+	ctx := context.Background()
+	variables := make(map[string]interface{})
+	// the steps run unmirrored, so a script that rolls back leaves the secondary alone
+	script := *d
+	script.mirror = nil
+	written := make([]scriptWrite, 0, len(steps))
+	connection, release := d.acquire()
+	defer release()
+	tx, err := connection.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	for i, step := range steps {
+		args, err := step.resolve(variables)
+		if err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("script step %d: %w", i+1, err)
+		}
+		if len(step.Capture) > 0 {
+			err = script.runCaptureStep(ctx, tx, step, args, variables)
+		} else {
+			var result sql.Result
+			result, err = script.runExecStep(ctx, tx, step, args, variables)
+			written = append(written, scriptWrite{query: step.Query, inserts: args, result: result})
+		}
+		if err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("script step %d: %w", i+1, err)
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+	if d.mirror != nil {
+		for _, write := range written {
+			d.mirror.write(ctx, write.query, write.inserts, write.result)
+		}
+	}
+	return variables, nil
+}
+
+// replaces ScriptVar args with the captured values
+func (s ScriptStep) resolve(variables map[string]interface{}) ([]interface{}, error) {
+	args := make([]interface{}, len(s.Args))
+	for i, arg := range s.Args {
+		name, ok := arg.(ScriptVar)
+		if !ok {
+			args[i] = arg
+			continue
+		}
+		value, ok := variables[string(name)]
+		if !ok {
+			return nil, fmt.Errorf("variable %s has not been captured", name)
+		}
+		args[i] = value
+	}
+	return args, nil
+}
+
+func (d *Database) runExecStep(ctx context.Context, tx *sql.Tx, step ScriptStep, args []interface{}, variables map[string]interface{}) (sql.Result, error) {
+	result, err := d.exec(ctx, tx, step.Query, args)
+	if err != nil {
+		return nil, err
+	}
+	if len(step.CaptureInsertID) < 1 {
+		return result, nil
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	variables[step.CaptureInsertID] = id
+	return result, nil
+}
+
+func (d *Database) runCaptureStep(ctx context.Context, tx *sql.Tx, step ScriptStep, args []interface{}, variables map[string]interface{}) error {
+	rows, err := tx.QueryContext(ctx, traceQuery(ctx, step.Query), d.normalizeArgs(args)...)
+	if err != nil {
+		return traceError(ctx, err)
+	}
+	result, err := d.parseRowResults(rows, QueryOptions{})
+	if err != nil {
+		return err
+	}
+	if len(result) < 1 {
+		return fmt.Errorf("no result to capture %v from", step.Capture)
+	}
+	for column, name := range step.Capture {
+		value, ok := result[0][column]
+		if !ok {
+			return fmt.Errorf("column %s not found", column)
+		}
+		variables[name] = value
+	}
+	return nil
+}
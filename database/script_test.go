@@ -0,0 +1,57 @@
+package database
+
+import (
+	"testing"
+)
+
+func TestRunScript(t *testing.T) {
+	defer recovery(t)
+	_, err := tdb.Exec("CREATE TABLE IF NOT EXISTS categories (id INT AUTO_INCREMENT PRIMARY KEY, name VARCHAR(100), parent_id INT)", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	variables, err := tdb.RunScript([]ScriptStep{
+		{Query: "insert into categories (name) values (?)", Args: []interface{}{"Hardware"}, CaptureInsertID: "parent_id"},
+		{Query: "insert into categories (name, parent_id) values (?, ?)", Args: []interface{}{"Tools", ScriptVar("parent_id")}},
+		{Query: "select count(*) as total from categories where parent_id = ?", Args: []interface{}{ScriptVar("parent_id")}, Capture: map[string]string{"total": "children"}},
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	if variables["children"] != int64(1) {
+		t.Errorf("expected one child to be captured, got %v", variables["children"])
+	}
+	parentID, ok := variables["parent_id"].(int64)
+	if !ok {
+		t.Fatalf("expected parent_id to be captured, got %v", variables["parent_id"])
+	}
+	row, err := tdb.Row("select parent_id from categories where name = 'Tools' and id > ?", parentID)
+	if err != nil {
+		t.Error(err)
+	}
+	if row["parent_id"] != parentID {
+		t.Errorf("expected the child to reference %d, got %v", parentID, row["parent_id"])
+	}
+}
+
+func TestRunScriptRollsBack(t *testing.T) {
+	defer recovery(t)
+	_, err := tdb.Exec("CREATE TABLE IF NOT EXISTS categories (id INT AUTO_INCREMENT PRIMARY KEY, name VARCHAR(100), parent_id INT)", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	_, err = tdb.RunScript([]ScriptStep{
+		{Query: "insert into categories (name) values (?)", Args: []interface{}{"Rolled Back"}, CaptureInsertID: "parent_id"},
+		{Query: "insert into categories (name, parent_id) values (?, ?)", Args: []interface{}{"Orphan", ScriptVar("missing")}},
+	})
+	if err == nil {
+		t.Errorf("expected an error for a variable that was not captured")
+	}
+	exists, err := tdb.Exists("select * from categories where name = ?", []interface{}{"Rolled Back"})
+	if err != nil {
+		t.Error(err)
+	}
+	if exists {
+		t.Errorf("expected the script to be rolled back")
+	}
+}
@@ -0,0 +1,214 @@
+package database
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Geometry is a spatial value as stored by MySQL: an SRID and the geometry's well-known binary
+type Geometry struct {
+	SRID uint32
+	WKB  []byte
+}
+
+// SpatialValue is a Record property written through ST_GeomFromText
+type SpatialValue struct {
+	WKT  string
+	SRID int
+}
+
+// GeomFromText makes a Record property that stores well-known text as a geometry
+func GeomFromText(wkt string, srid int) SpatialValue {
+	return SpatialValue{WKT: wkt, SRID: srid}
+}
+
+func (s SpatialValue) expression() (string, []interface{}, error) {
+	return "ST_GeomFromText(?, ?)", []interface{}{s.WKT, s.SRID}, nil
+}
+
+// WithinRadius builds a WHERE condition matching points in a column within the given distance
+// in meters of a latitude and longitude, using the column's SRID
+func WithinRadius(column string, lat, lng, meters float64) (string, []interface{}) {
+	column = quoteIdentifier(column)
+	return fmt.Sprintf("ST_Distance_Sphere(%s, %s) <= ?", column, geomFromTextFor(column)),
+		[]interface{}{"POINT(" + formatCoordinate(lng, lat) + ")", meters}
+}
+
+// WithinPolygon builds a WHERE condition matching geometries in a column that lie inside the
+// polygon with the given [latitude, longitude] vertices, using the column's SRID
+func WithinPolygon(column string, vertices [][2]float64) (string, []interface{}, error) {
+	if len(vertices) < 3 {
+		return "", nil, errors.New("a polygon needs at least 3 vertices")
+	}
+	if vertices[0] != vertices[len(vertices)-1] {
+		vertices = append(vertices, vertices[0])
+	}
+	ring := make([]string, 0, len(vertices))
+	for _, vertex := range vertices {
+		ring = append(ring, formatCoordinate(vertex[1], vertex[0]))
+	}
+	column = quoteIdentifier(column)
+	return fmt.Sprintf("ST_Contains(%s, %s)", geomFromTextFor(column), column),
+		[]interface{}{"POLYGON((" + strings.Join(ring, ",") + "))"},
+		nil
+}
+
+// builds a geometry from bound WKT in a column's SRID, reading coordinates as longitude then latitude
+func geomFromTextFor(column string) string {
+	return fmt.Sprintf("ST_GeomFromText(?, ST_SRID(%s), 'axis-order=long-lat')", column)
+}
+
+func formatCoordinate(x, y float64) string {
+	return strconv.FormatFloat(x, 'f', -1, 64) + " " + strconv.FormatFloat(y, 'f', -1, 64)
+}
+
+// WKT decodes the geometry's well-known binary into well-known text
+func (g Geometry) WKT() (string, error) {
+	reader := &wkbReader{data: g.WKB}
+	wkt, err := reader.geometry()
+	if err != nil {
+		return "", err
+	}
+	return wkt, nil
+}
+
+// nullGeometry scans spatial columns from MySQL's internal format (a little-endian SRID followed by WKB)
+type nullGeometry struct {
+	Geometry Geometry
+	Valid    bool
+}
+
+func (n *nullGeometry) Scan(src interface{}) error {
+	if src == nil {
+		n.Geometry, n.Valid = Geometry{}, false
+		return nil
+	}
+	raw, ok := src.([]byte)
+	if !ok {
+		return fmt.Errorf("cannot scan %T into a geometry", src)
+	}
+	if len(raw) < 4 {
+		return errors.New("geometry value is too short")
+	}
+	n.Geometry = Geometry{
+		SRID: binary.LittleEndian.Uint32(raw[:4]),
+		WKB:  append([]byte{}, raw[4:]...),
+	}
+	n.Valid = true
+	return nil
+}
+
+func (n nullGeometry) result() (interface{}, bool) {
+	return n.Geometry, n.Valid
+}
+
+func scanNullGeometry() interface{} {
+	var newCol nullGeometry
+	return &newCol
+}
+
+var wkbTypes = map[uint32]string{
+	1: "POINT",
+	2: "LINESTRING",
+	3: "POLYGON",
+	4: "MULTIPOINT",
+	5: "MULTILINESTRING",
+	6: "MULTIPOLYGON",
+	7: "GEOMETRYCOLLECTION",
+}
+
+type wkbReader struct {
+	data  []byte
+	order binary.ByteOrder
+}
+
+func (r *wkbReader) geometry() (string, error) {
+	if len(r.data) < 5 {
+		return "", errors.New("geometry value is too short")
+	}
+	if r.data[0] == 0 {
+		r.order = binary.BigEndian
+	} else {
+		r.order = binary.LittleEndian
+	}
+	r.data = r.data[1:]
+	kind, err := r.uint32()
+	if err != nil {
+		return "", err
+	}
+	name, ok := wkbTypes[kind]
+	if !ok {
+		return "", fmt.Errorf("unsupported geometry type %d", kind)
+	}
+	body, err := r.body(kind)
+	if err != nil {
+		return "", err
+	}
+	return name + body, nil
+}
+
+func (r *wkbReader) body(kind uint32) (string, error) {
+	switch kind {
+	case 1:
+		point, err := r.point()
+		return "(" + point + ")", err
+	case 2:
+		return r.points()
+	case 3:
+		return r.repeat(r.points)
+	case 4, 5, 6:
+		// members of multi geometries repeat their header, which WKT omits
+		return r.repeat(func() (string, error) {
+			wkt, err := r.geometry()
+			if err != nil {
+				return "", err
+			}
+			return strings.TrimLeft(wkt, "ABCDEFGHIJKLMNOPQRSTUVWXYZ"), nil
+		})
+	default:
+		return r.repeat(r.geometry)
+	}
+}
+
+func (r *wkbReader) repeat(read func() (string, error)) (string, error) {
+	count, err := r.uint32()
+	if err != nil {
+		return "", err
+	}
+	parts := make([]string, 0, count)
+	for i := uint32(0); i < count; i++ {
+		part, err := read()
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, part)
+	}
+	return "(" + strings.Join(parts, ",") + ")", nil
+}
+
+func (r *wkbReader) points() (string, error) {
+	return r.repeat(r.point)
+}
+
+func (r *wkbReader) point() (string, error) {
+	if len(r.data) < 16 {
+		return "", errors.New("geometry point is too short")
+	}
+	x := math.Float64frombits(r.order.Uint64(r.data[:8]))
+	y := math.Float64frombits(r.order.Uint64(r.data[8:16]))
+	r.data = r.data[16:]
+	return strconv.FormatFloat(x, 'f', -1, 64) + " " + strconv.FormatFloat(y, 'f', -1, 64), nil
+}
+
+func (r *wkbReader) uint32() (uint32, error) {
+	if len(r.data) < 4 {
+		return 0, errors.New("geometry value is too short")
+	}
+	value := r.order.Uint32(r.data[:4])
+	r.data = r.data[4:]
+	return value, nil
+}
@@ -0,0 +1,98 @@
+package database
+
+import (
+	"testing"
+)
+
+func TestSpatialColumns(t *testing.T) {
+	defer recovery(t)
+	_, err := tdb.Exec("CREATE TABLE IF NOT EXISTS places (id INT AUTO_INCREMENT PRIMARY KEY, location POINT, area POLYGON)", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	id, err := tdb.MakeRecord(map[string]interface{}{
+		"location": GeomFromText("POINT(1.5 -2)", 0),
+		"area":     GeomFromText("POLYGON((0 0,4 0,4 4,0 4,0 0))", 0),
+	}, "places").Create()
+	if err != nil {
+		t.Error(err)
+	}
+	row, err := tdb.Row("select location, area from places where id = ?", id)
+	if err != nil {
+		t.Error(err)
+	}
+	expected := map[string]string{
+		"location": "POINT(1.5 -2)",
+		"area":     "POLYGON((0 0,4 0,4 4,0 4,0 0))",
+	}
+	for col, wkt := range expected {
+		geometry, ok := row[col].(Geometry)
+		if !ok {
+			t.Errorf("expected %s to be a Geometry, got %T", col, row[col])
+			continue
+		}
+		decoded, err := geometry.WKT()
+		if err != nil {
+			t.Error(err)
+		}
+		if decoded != wkt {
+			t.Errorf("expected %s to be %s, got %s", col, wkt, decoded)
+		}
+	}
+}
+
+func TestSpatialConditions(t *testing.T) {
+	defer recovery(t)
+	_, err := tdb.Exec("CREATE TABLE IF NOT EXISTS stores (id INT AUTO_INCREMENT PRIMARY KEY, name VARCHAR(100), location POINT SRID 4326)", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	stores := map[string]string{
+		"Cape Town":    "POINT(-33.9249 18.4241)",
+		"Stellenbosch": "POINT(-33.9321 18.8602)",
+		"Durban":       "POINT(-29.8587 31.0218)",
+	}
+	for name, location := range stores {
+		_, err = tdb.MakeRecord(map[string]interface{}{
+			"name":     name,
+			"location": GeomFromText(location, 4326),
+		}, "stores").Create()
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	condition, escaped := WithinRadius("location", -33.9249, 18.4241, 50000)
+	rows, err := tdb.QueryRaw("select name from stores where "+condition+" order by name", escaped)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(rows) != 2 || rows[0]["name"] != "Cape Town" || rows[1]["name"] != "Stellenbosch" {
+		t.Errorf("expected Cape Town and Stellenbosch within 50km, got %v", rows)
+	}
+	condition, escaped, err = WithinPolygon("location", [][2]float64{{-31, 30}, {-31, 32}, {-29, 32}, {-29, 30}})
+	if err != nil {
+		t.Error(err)
+	}
+	rows, err = tdb.QueryRaw("select name from stores where "+condition, escaped)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(rows) != 1 || rows[0]["name"] != "Durban" {
+		t.Errorf("expected only Durban within the polygon, got %v", rows)
+	}
+}
+
+func TestGeometryWKT(t *testing.T) {
+	multi := Geometry{WKB: []byte{
+		1, 4, 0, 0, 0, 2, 0, 0, 0,
+		1, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 240, 63, 0, 0, 0, 0, 0, 0, 0, 64,
+		1, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 8, 64, 0, 0, 0, 0, 0, 0, 16, 64,
+	}}
+	wkt, err := multi.WKT()
+	if err != nil {
+		t.Error(err)
+	}
+	if wkt != "MULTIPOINT((1 2),(3 4))" {
+		t.Errorf("expected MULTIPOINT((1 2),(3 4)), got %s", wkt)
+	}
+}
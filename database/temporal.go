@@ -0,0 +1,64 @@
+package database
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FormatTimeValue formats a duration as a MySQL TIME value, e.g. -838:59:59 or 12:30:00.250000.
+// time.Duration Record properties are written this way
+func FormatTimeValue(duration time.Duration) string {
+	sign := ""
+	if duration < 0 {
+		sign = "-"
+		duration = -duration
+	}
+	hours := duration / time.Hour
+	minutes := duration % time.Hour / time.Minute
+	seconds := duration % time.Minute / time.Second
+	micros := duration % time.Second / time.Microsecond
+	formatted := fmt.Sprintf("%s%02d:%02d:%02d", sign, hours, minutes, seconds)
+	if micros > 0 {
+		formatted += fmt.Sprintf(".%06d", micros)
+	}
+	return formatted
+}
+
+// ParseTimeValue parses a MySQL TIME value in the HH:MM:SS[.fraction] form returned by the server
+func ParseTimeValue(value string) (time.Duration, error) {
+	invalid := fmt.Errorf("invalid TIME value '%s'", value)
+	negative := strings.HasPrefix(value, "-")
+	parts := strings.Split(strings.TrimPrefix(value, "-"), ":")
+	if len(parts) != 3 {
+		return 0, invalid
+	}
+	fraction := ""
+	if dot := strings.Index(parts[2], "."); dot >= 0 {
+		parts[2], fraction = parts[2][:dot], parts[2][dot+1:]
+	}
+	units := []time.Duration{time.Hour, time.Minute, time.Second}
+	var duration time.Duration
+	for i, part := range parts {
+		number, err := strconv.ParseUint(part, 10, 32)
+		if err != nil {
+			return 0, invalid
+		}
+		duration += time.Duration(number) * units[i]
+	}
+	if len(fraction) > 0 {
+		if len(fraction) > 9 {
+			return 0, invalid
+		}
+		nanos, err := strconv.ParseUint(fraction+strings.Repeat("0", 9-len(fraction)), 10, 64)
+		if err != nil {
+			return 0, invalid
+		}
+		duration += time.Duration(nanos)
+	}
+	if negative {
+		duration = -duration
+	}
+	return duration, nil
+}
@@ -0,0 +1,62 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeValueRoundTrip(t *testing.T) {
+	values := map[string]time.Duration{
+		"00:00:00":         0,
+		"12:30:05":         12*time.Hour + 30*time.Minute + 5*time.Second,
+		"-838:59:59":       -(838*time.Hour + 59*time.Minute + 59*time.Second),
+		"01:00:00.250000":  time.Hour + 250*time.Millisecond,
+		"100:00:00.000001": 100*time.Hour + time.Microsecond,
+	}
+	for formatted, duration := range values {
+		if result := FormatTimeValue(duration); result != formatted {
+			t.Errorf("expected %v to format as '%s', got '%s'", duration, formatted, result)
+		}
+		parsed, err := ParseTimeValue(formatted)
+		if err != nil {
+			t.Error(err)
+		}
+		if parsed != duration {
+			t.Errorf("expected '%s' to parse as %v, got %v", formatted, duration, parsed)
+		}
+	}
+	if _, err := ParseTimeValue("12:30"); err == nil {
+		t.Errorf("expected an error for an incomplete TIME value")
+	}
+}
+
+func TestYearAndTimeColumns(t *testing.T) {
+	defer recovery(t)
+	_, err := tdb.Exec("CREATE TABLE IF NOT EXISTS shifts (id INT AUTO_INCREMENT PRIMARY KEY, season YEAR, starts TIME(6), length TIME)", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	starts := 8*time.Hour + 30*time.Minute + 500*time.Millisecond
+	length := -(30 * time.Hour)
+	id, err := tdb.MakeRecord(map[string]interface{}{
+		"season": 2024,
+		"starts": starts,
+		"length": length,
+	}, "shifts").Create()
+	if err != nil {
+		t.Error(err)
+	}
+	row, err := tdb.Row("select season, starts, length from shifts where id = ?", id)
+	if err != nil {
+		t.Error(err)
+	}
+	if row["season"] != int64(2024) {
+		t.Errorf("expected season to be 2024, got %v (%T)", row["season"], row["season"])
+	}
+	if row["starts"] != starts {
+		t.Errorf("expected starts to be %v, got %v (%T)", starts, row["starts"], row["starts"])
+	}
+	if row["length"] != length {
+		t.Errorf("expected length to be %v, got %v (%T)", length, row["length"], row["length"])
+	}
+}
@@ -0,0 +1,94 @@
+package database
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// MySQL error numbers for transactions that can be rerun from the start
+const (
+	errorDeadlock      = 1213
+	errorWriteConflict = 9007
+)
+
+// the write conflict retries of the TiDB profile
+const tidbWriteConflictRetries = 3
+
+// IsTiDB reports whether the server is TiDB rather than MySQL
+func (d *Database) IsTiDB() (bool, error) {
+	version, err := d.ServerVersion()
+	if err != nil {
+		return false, err
+	}
+	return isTiDBVersion(version), nil
+}
+
+func isTiDBVersion(version string) bool {
+	return strings.Contains(strings.ToLower(version), "tidb")
+}
+
+// how many times to rerun a transaction that fails on a write conflict
+func (d *Database) writeConflictRetries() int {
+	if d.configs.WriteConflictRetries == 0 && d.configs.TiDB {
+		return tidbWriteConflictRetries
+	}
+	return d.configs.WriteConflictRetries
+}
+
+// DeleteInBatches deletes the rows of a table matching the condition batchSize rows per statement, so
+// no one transaction grows past TiDB's size limits or holds MySQL's locks for long. Unlike TiDB's
+// BATCH statements, it runs on MySQL too and counts the rows deleted. A batch that fails on a write
+// conflict is rerun as RunScript's transactions are. It returns the number of rows deleted
+func (d *Database) DeleteInBatches(table, condition string, values []interface{}, batchSize int) (int64, error) {
+	if len(condition) < 1 {
+		return 0, errors.New("no condition to delete rows by")
+	}
+	if batchSize < 1 {
+		return 0, errors.New("the batch size must be at least one")
+	}
+	statement := "DELETE FROM " + quoteIdentifier(d.Name()) + "." + quoteIdentifier(table) +
+		" WHERE " + condition + " LIMIT " + strconv.Itoa(batchSize)
+	return d.deleteInBatches(statement, values, batchSize, nil)
+}
+
+// runs a DELETE ... LIMIT until it deletes fewer rows than the limit, reporting each batch's count
+func (d *Database) deleteInBatches(statement string, values []interface{}, batchSize int, report func(int64)) (int64, error) {
+	var total int64
+	for {
+		affected, err := d.deleteBatch(statement, values)
+		if err != nil {
+			return total, err
+		}
+		total += affected
+		if report != nil {
+			report(affected)
+		}
+		if affected < int64(batchSize) {
+			return total, nil
+		}
+	}
+}
+
+func (d *Database) deleteBatch(statement string, values []interface{}) (int64, error) {
+	for attempt := 0; ; attempt++ {
+		result, err := d.Exec(statement, values)
+		if err == nil {
+			return result.RowsAffected()
+		}
+		if attempt >= d.writeConflictRetries() || !isWriteConflict(err) {
+			return 0, err
+		}
+	}
+}
+
+// reports whether the server rolled a transaction back on a write conflict or deadlock
+func isWriteConflict(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return false
+	}
+	return mysqlErr.Number == errorWriteConflict || mysqlErr.Number == errorDeadlock
+}
@@ -0,0 +1,76 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestIsTiDBVersion(t *testing.T) {
+	if !isTiDBVersion("5.7.25-TiDB-v7.1.0") {
+		t.Errorf("expected a TiDB version to be detected")
+	}
+	if isTiDBVersion("8.0.32") {
+		t.Errorf("expected a MySQL version not to be detected as TiDB")
+	}
+}
+
+func TestIsWriteConflict(t *testing.T) {
+	conflict := fmt.Errorf("script step 2: %w", &mysql.MySQLError{Number: 9007, Message: "Write conflict"})
+	if !isWriteConflict(conflict) {
+		t.Errorf("expected a wrapped TiDB write conflict to be detected")
+	}
+	if !isWriteConflict(&mysql.MySQLError{Number: 1213}) {
+		t.Errorf("expected a deadlock to be detected")
+	}
+	if isWriteConflict(&mysql.MySQLError{Number: 1062}) || isWriteConflict(errors.New("write conflict")) {
+		t.Errorf("expected other errors not to be retried")
+	}
+}
+
+func TestWriteConflictRetries(t *testing.T) {
+	d := &Database{configs: &Configs{}}
+	if d.writeConflictRetries() != 0 {
+		t.Errorf("expected no retries by default")
+	}
+	d.configs.TiDB = true
+	if d.writeConflictRetries() != tidbWriteConflictRetries {
+		t.Errorf("expected the TiDB profile to retry write conflicts")
+	}
+	d.configs.WriteConflictRetries = 1
+	if d.writeConflictRetries() != 1 {
+		t.Errorf("expected WriteConflictRetries to take precedence over the TiDB profile")
+	}
+}
+
+func TestDeleteInBatches(t *testing.T) {
+	defer recovery(t)
+	_, err := tdb.Exec("CREATE TABLE IF NOT EXISTS batch_events (id INT AUTO_INCREMENT PRIMARY KEY, kind VARCHAR(20))", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	for i := 0; i < 7; i++ {
+		kind := "stale"
+		if i%2 == 0 {
+			kind = "fresh"
+		}
+		_, err = tdb.MakeRecord(map[string]interface{}{"kind": kind}, "batch_events").Create()
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	deleted, err := tdb.DeleteInBatches("batch_events", "kind = ?", []interface{}{"stale"}, 2)
+	if err != nil || deleted != 3 {
+		t.Errorf("expected 3 rows to be deleted, got %d %v", deleted, err)
+	}
+	rows, err := tdb.QueryRaw("select count(*) as total from batch_events where kind = 'fresh'", nil)
+	if err != nil || rows[0]["total"] != int64(4) {
+		t.Errorf("expected the other rows to be left, got %v %v", rows, err)
+	}
+	_, err = tdb.DeleteInBatches("batch_events", "", nil, 2)
+	if err == nil {
+		t.Errorf("expected a delete without a condition to be refused")
+	}
+}
@@ -0,0 +1,84 @@
+package database
+
+import (
+	"sync"
+	"time"
+)
+
+// columnCache holds the columns of each table, looked up once per Database
+type columnCache struct {
+	mu     sync.Mutex
+	tables map[string]map[string]bool
+}
+
+// HasColumn reports whether a table has a column
+func (d *Database) HasColumn(table, column string) (bool, error) {
+	columns, err := d.tableColumns(table)
+	if err != nil {
+		return false, err
+	}
+	return columns[column], nil
+}
+
+func (d *Database) tableColumns(table string) (map[string]bool, error) {
+	d.columns.mu.Lock()
+	defer d.columns.mu.Unlock()
+	if columns, ok := d.columns.tables[table]; ok {
+		return columns, nil
+	}
+	rows, err := d.QueryRaw(
+		"SELECT column_name AS column_name FROM information_schema.columns WHERE table_schema = ? AND table_name = ?",
+		[]interface{}{d.Name(), table},
+	)
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]bool)
+	for _, row := range rows {
+		name, _ := row["column_name"].(string)
+		columns[name] = true
+	}
+	d.columns.tables[table] = columns
+	return columns, nil
+}
+
+// now returns the time from the configured clock
+func (d *Database) now() time.Time {
+	if d.configs.Clock != nil {
+		return d.configs.Clock()
+	}
+	return time.Now()
+}
+
+func (d *Database) timestamped(table string) bool {
+	for _, timestamped := range d.configs.Timestamps {
+		if timestamped == "*" || timestamped == table {
+			return true
+		}
+	}
+	return false
+}
+
+// sets created_at (when creating) and updated_at on records of timestamped tables. Values the
+// caller set themselves are kept
+func (r *Record) touch(creating bool) error {
+	if !r.database.timestamped(r.table) {
+		return nil
+	}
+	columns, err := r.database.tableColumns(r.table)
+	if err != nil {
+		return err
+	}
+	now := r.database.now()
+	if creating && columns["created_at"] && r.properties["created_at"] == nil {
+		r.Set("created_at", now)
+	}
+	if !columns["updated_at"] {
+		return nil
+	}
+	if _, ok := r.properties["updated_at"]; ok && r.dirty("updated_at") && r.properties["updated_at"] != nil {
+		return nil
+	}
+	r.Set("updated_at", now)
+	return nil
+}
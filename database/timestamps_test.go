@@ -0,0 +1,53 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimestamps(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	now := time.Date(2024, time.May, 1, 8, 0, 0, 0, time.UTC)
+	configs := getConfigs(false)
+	configs.Timestamps = []string{"widgets"}
+	configs.Clock = func() time.Time {
+		return now
+	}
+	d, err := Make(configs)
+	if err != nil {
+		t.Error(err)
+	}
+	defer d.Close()
+	hasColumn, err := d.HasColumn("widgets", "updated_at")
+	if err != nil {
+		t.Error(err)
+	}
+	if !hasColumn {
+		t.Errorf("expected widgets to have an updated_at column")
+	}
+	record := d.MakeRecord(map[string]interface{}{"sku": "STAMP1"}, "widgets")
+	id, err := record.Save()
+	if err != nil {
+		t.Error(err)
+	}
+	created, err := record.GetTime("created_at")
+	if err != nil || !created.Equal(now) {
+		t.Errorf("expected created_at to be set from the clock, got %v (%v)", created, err)
+	}
+	now = now.Add(time.Hour)
+	_, err = record.Set("description", "Stamped Widget").Save()
+	if err != nil {
+		t.Error(err)
+	}
+	row, err := d.Row("select created_at, updated_at from widgets where id = ?", id)
+	if err != nil {
+		t.Error(err)
+	}
+	if at, _ := row["created_at"].(time.Time); !at.Equal(now.Add(-time.Hour)) {
+		t.Errorf("expected created_at to be kept, got %v", row["created_at"])
+	}
+	if at, _ := row["updated_at"].(time.Time); !at.Equal(now) {
+		t.Errorf("expected updated_at to be refreshed, got %v", row["updated_at"])
+	}
+}
@@ -0,0 +1,144 @@
+package database
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// ErrInvalidToken is returned when a token doesn't match a stored token
+var ErrInvalidToken = errors.New("invalid token")
+
+// Hasher hashes secrets for storage and checks secrets against stored hashes. Plug in a slow hasher,
+// such as one wrapping golang.org/x/crypto/bcrypt or argon2, for passwords
+type Hasher interface {
+	Hash(secret string) (string, error)
+	Verify(secret, hash string) (bool, error)
+}
+
+// SHA256Hasher hashes secrets with SHA-256. It suits long random tokens, which can't be guessed,
+// but not passwords
+type SHA256Hasher struct{}
+
+// Hash returns the hex SHA-256 digest of the secret
+func (SHA256Hasher) Hash(secret string) (string, error) {
+	digest := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(digest[:]), nil
+}
+
+// Verify compares the secret's digest with the hash in constant time
+func (h SHA256Hasher) Verify(secret, hash string) (bool, error) {
+	digest, _ := h.Hash(secret)
+	return subtle.ConstantTimeCompare([]byte(digest), []byte(hash)) == 1, nil
+}
+
+// TokenOptions configures a TokenStore
+type TokenOptions struct {
+	// PrefixColumn holds the token's public prefix, which tokens are looked up by; defaults to "prefix"
+	PrefixColumn string
+	// HashColumn holds the hash of the token's secret part; defaults to "token_hash"
+	HashColumn string
+	// Hasher defaults to SHA256Hasher
+	Hasher Hasher
+}
+
+// TokenStore issues API tokens and verifies them against hashes stored in a table. Tokens are
+// "prefix.secret"; only the secret's hash is stored, so a leaked table can't be used to authenticate
+type TokenStore struct {
+	database *Database
+	table    string
+	options  TokenOptions
+}
+
+// Tokens makes a TokenStore for a table
+func (d *Database) Tokens(table string, options TokenOptions) *TokenStore {
+	if len(options.PrefixColumn) < 1 {
+		options.PrefixColumn = "prefix"
+	}
+	if len(options.HashColumn) < 1 {
+		options.HashColumn = "token_hash"
+	}
+	if options.Hasher == nil {
+		options.Hasher = SHA256Hasher{}
+	}
+	return &TokenStore{database: d, table: table, options: options}
+}
+
+// Issue creates a token row with the given properties, such as its owner, and returns the token.
+// The token can't be recovered from the row, so it must be handed to its user now
+func (s *TokenStore) Issue(properties map[string]interface{}) (string, *Record, error) {
+	prefix, err := randomText(6)
+	if err != nil {
+		return "", nil, err
+	}
+	secret, err := randomText(32)
+	if err != nil {
+		return "", nil, err
+	}
+	record := s.database.MakeRecord(mergeAttributes(properties, nil), s.table)
+	err = record.SetHashed(s.options.HashColumn, secret, s.options.Hasher)
+	if err != nil {
+		return "", nil, err
+	}
+	_, err = record.Set(s.options.PrefixColumn, prefix).Save()
+	if err != nil {
+		return "", nil, err
+	}
+	return prefix + "." + secret, record, nil
+}
+
+// Verify returns the row of a token, or ErrInvalidToken when it doesn't match a stored token
+func (s *TokenStore) Verify(token string) (*Record, error) {
+	prefix, secret, ok := strings.Cut(token, ".")
+	if !ok || len(prefix) < 1 || len(secret) < 1 {
+		return nil, ErrInvalidToken
+	}
+	record, err := s.database.FindRecordBy(s.table, map[string]interface{}{s.options.PrefixColumn: prefix})
+	if err != nil {
+		if errors.Is(err, ErrNoResult) {
+			return nil, ErrInvalidToken
+		}
+		return nil, err
+	}
+	valid, err := record.VerifyHashed(s.options.HashColumn, secret, s.options.Hasher)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, ErrInvalidToken
+	}
+	return record, nil
+}
+
+// SetHashed sets a property to the hash of a secret, such as a password
+func (r *Record) SetHashed(column, secret string, hasher Hasher) error {
+	hash, err := hasher.Hash(secret)
+	if err != nil {
+		return err
+	}
+	r.Set(column, hash)
+	return nil
+}
+
+// VerifyHashed reports whether a secret matches the hash held in a property
+func (r *Record) VerifyHashed(column, secret string, hasher Hasher) (bool, error) {
+	hash, err := r.GetString(column)
+	if err != nil {
+		return false, err
+	}
+	return hasher.Verify(secret, hash)
+}
+
+// random URL-safe text from the given number of random bytes
+func randomText(size int) (string, error) {
+	random := make([]byte, size)
+	_, err := rand.Read(random)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(random), nil
+}
@@ -0,0 +1,53 @@
+package database
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestHashedSecrets(t *testing.T) {
+	d := &Database{configs: &Configs{}}
+	record := d.MakeRecord(map[string]interface{}{"email": "jane@example.com"}, "users")
+	err := record.SetHashed("password_hash", "correct horse", SHA256Hasher{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash, _ := record.GetString("password_hash"); len(hash) != 64 || strings.Contains(hash, "horse") {
+		t.Errorf("expected a hex digest, got %s", hash)
+	}
+	valid, err := record.VerifyHashed("password_hash", "correct horse", SHA256Hasher{})
+	if err != nil || !valid {
+		t.Errorf("expected the secret to verify, got %v %v", valid, err)
+	}
+	valid, err = record.VerifyHashed("password_hash", "battery staple", SHA256Hasher{})
+	if err != nil || valid {
+		t.Errorf("expected a different secret not to verify, got %v %v", valid, err)
+	}
+}
+
+func TestTokenStore(t *testing.T) {
+	defer recovery(t)
+	_, err := tdb.Exec("CREATE TABLE IF NOT EXISTS api_tokens (id INT AUTO_INCREMENT PRIMARY KEY, user_id INT, prefix VARCHAR(16) UNIQUE, token_hash CHAR(64))", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	tokens := tdb.Tokens("api_tokens", TokenOptions{})
+	token, _, err := tokens.Issue(map[string]interface{}{"user_id": 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	record, err := tokens.Verify(token)
+	if err != nil {
+		t.Error(err)
+	}
+	if userID, _ := record.GetInt64("user_id"); userID != 7 {
+		t.Errorf("expected the token to belong to user 7, got %d", userID)
+	}
+	prefix, _, _ := strings.Cut(token, ".")
+	for _, invalid := range []string{prefix + ".wrong", "missing.secret", "no-separator"} {
+		if _, err = tokens.Verify(invalid); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("expected %s to be invalid, got %v", invalid, err)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// the longest trace ID kept in query comments and errors
+const maxTraceID = 64
+
+type traceKey struct{}
+
+// WithTraceID returns a copy of ctx carrying a request or trace ID. Queries run with the context through
+// QueryOptions.Context or Record.WithContext end with a /* trace_id=... */ comment, so the slow log and
+// processlist show the ID, and their errors include it
+func WithTraceID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, traceKey{}, id)
+}
+
+// TraceID returns the trace ID carried by ctx, sanitized as it appears in queries
+func TraceID(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	id, ok := ctx.Value(traceKey{}).(string)
+	if !ok {
+		return "", false
+	}
+	id = sanitizeTraceID(id)
+	return id, len(id) > 0
+}
+
+// keeps letters, digits and the separators IDs commonly use, so an ID can't close the comment
+// or inject SQL
+func sanitizeTraceID(id string) string {
+	sanitized := make([]byte, 0, len(id))
+	for i := 0; i < len(id) && len(sanitized) < maxTraceID; i++ {
+		c := id[i]
+		if (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == ':' {
+			sanitized = append(sanitized, c)
+		}
+	}
+	return string(sanitized)
+}
+
+// appends the context's trace ID to the query as a comment, ahead of any trailing semicolon
+// as a comment after it would be sent as a second statement
+func traceQuery(ctx context.Context, query string) string {
+	id, ok := TraceID(ctx)
+	if !ok {
+		return query
+	}
+	return strings.TrimRight(query, "; \t\r\n") + " /* trace_id=" + id + " */"
+}
+
+// adds the context's trace ID to an error
+func traceError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	id, ok := TraceID(ctx)
+	if !ok {
+		return err
+	}
+	return fmt.Errorf("%w (trace_id %s)", err, id)
+}
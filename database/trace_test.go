@@ -0,0 +1,52 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTraceQuery(t *testing.T) {
+	ctx := WithTraceID(context.Background(), "req-42 */ DROP TABLE users; /*")
+	id, ok := TraceID(ctx)
+	if !ok || id != "req-42DROPTABLEusers" {
+		t.Errorf("expected the trace ID to be sanitized, got %q", id)
+	}
+	query := traceQuery(ctx, "SELECT 1;")
+	if query != "SELECT 1 /* trace_id=req-42DROPTABLEusers */" {
+		t.Errorf("unexpected traced query %q", query)
+	}
+	if query = traceQuery(context.Background(), "SELECT 1"); query != "SELECT 1" {
+		t.Errorf("expected queries without a trace ID to be unchanged, got %q", query)
+	}
+	if _, ok = TraceID(WithTraceID(context.Background(), "*/")); ok {
+		t.Errorf("expected an ID with nothing left after sanitizing to be ignored")
+	}
+	cause := errors.New("no result")
+	err := traceError(ctx, cause)
+	if !errors.Is(err, cause) || !strings.Contains(err.Error(), "trace_id req-42DROPTABLEusers") {
+		t.Errorf("expected the error to wrap the cause and carry the trace ID, got %v", err)
+	}
+}
+
+func TestTraceIDOnQueries(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	ctx := WithTraceID(context.Background(), "trace-widgets")
+	rows, err := tdb.QueryRawWithOptions("SELECT INFO FROM information_schema.PROCESSLIST WHERE ID = CONNECTION_ID()", nil, QueryOptions{Context: ctx})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 || !strings.Contains(rows[0]["INFO"].(string), "/* trace_id=trace-widgets */") {
+		t.Errorf("expected the running query to carry the trace comment, got %v", rows)
+	}
+	_, err = tdb.ExecWithOptions("UPDATE widgets SET no_such_column = 1", nil, QueryOptions{Context: ctx})
+	if err == nil || !strings.Contains(err.Error(), "trace_id trace-widgets") {
+		t.Errorf("expected the error to carry the trace ID, got %v", err)
+	}
+	_, err = tdb.MakeRecord(map[string]interface{}{"no_such_column": 1}, "widgets").WithContext(ctx).Create()
+	if err == nil || !strings.Contains(err.Error(), "trace_id trace-widgets") {
+		t.Errorf("expected Record errors to carry the trace ID, got %v", err)
+	}
+}
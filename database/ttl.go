@@ -0,0 +1,157 @@
+package database
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// SweeperOptions configures the background sweeper started by StartSweeper
+type SweeperOptions struct {
+	// Interval is how often expired rows are swept; defaults to one minute
+	Interval time.Duration
+	// BatchSize is the number of rows deleted per statement; defaults to 1000
+	BatchSize int
+	// OnError is called when a sweep of a table fails
+	OnError func(table string, err error)
+}
+
+// TTLStats counts the rows the sweeper has deleted, per table
+type TTLStats struct {
+	Sweeps    uint64
+	Reclaimed map[string]uint64
+}
+
+type ttlRule struct {
+	column string
+	ttl    time.Duration
+}
+
+// ttlRegistry holds the row TTLs declared with SetTTL and the sweeper that enforces them
+type ttlRegistry struct {
+	mu        sync.Mutex
+	rules     map[string]ttlRule
+	sweeps    uint64
+	reclaimed map[string]uint64
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+func newTTLRegistry() *ttlRegistry {
+	return &ttlRegistry{rules: make(map[string]ttlRule), reclaimed: make(map[string]uint64)}
+}
+
+// SetTTL expires the rows of a table once the time in the column is older than the TTL, e.g. sessions
+// by their created_at. Expired rows are deleted by SweepExpired or the background sweeper
+func (d *Database) SetTTL(table, column string, ttl time.Duration) {
+	d.ttl.mu.Lock()
+	defer d.ttl.mu.Unlock()
+	d.ttl.rules[table] = ttlRule{column: column, ttl: ttl}
+}
+
+// SweepExpired deletes the expired rows of every table with a TTL, in batches so each statement holds
+// locks briefly. It returns the number of rows deleted per table and the first error, carrying on
+// with the other tables after one fails
+func (d *Database) SweepExpired(batchSize int) (map[string]int64, error) {
+	deleted := make(map[string]int64)
+	var failed error
+	d.sweepTables(batchSize, func(table string, count int64, err error) {
+		deleted[table] = count
+		if err != nil && failed == nil {
+			failed = err
+		}
+	})
+	return deleted, failed
+}
+
+// sweeps each table with a TTL in name order, reporting how many rows were deleted
+func (d *Database) sweepTables(batchSize int, report func(table string, deleted int64, err error)) {
+	if batchSize < 1 {
+		batchSize = 1000
+	}
+	d.ttl.mu.Lock()
+	rules := make(map[string]ttlRule, len(d.ttl.rules))
+	tables := make([]string, 0, len(d.ttl.rules))
+	for table, rule := range d.ttl.rules {
+		rules[table] = rule
+		tables = append(tables, table)
+	}
+	d.ttl.mu.Unlock()
+	sort.Strings(tables)
+	for _, table := range tables {
+		count, err := d.sweep(table, rules[table], batchSize)
+		report(table, count, err)
+	}
+}
+
+func (d *Database) sweep(table string, rule ttlRule, batchSize int) (int64, error) {
+	cutoff := d.now().Add(-rule.ttl)
+	statement := "DELETE FROM " + quoteIdentifier(d.Name()) + "." + quoteIdentifier(table) +
+		" WHERE " + quoteIdentifier(rule.column) + " < ? LIMIT " + strconv.Itoa(batchSize)
+	return d.deleteInBatches(statement, []interface{}{cutoff}, batchSize, func(affected int64) {
+		d.ttl.mu.Lock()
+		d.ttl.reclaimed[table] += uint64(affected)
+		d.ttl.mu.Unlock()
+	})
+}
+
+// StartSweeper sweeps expired rows in the background until StopSweeper is called or the database is closed
+func (d *Database) StartSweeper(options SweeperOptions) {
+	d.StopSweeper()
+	if options.Interval <= 0 {
+		options.Interval = time.Minute
+	}
+	d.ttl.mu.Lock()
+	stop, done := make(chan struct{}), make(chan struct{})
+	d.ttl.stop, d.ttl.done = stop, done
+	d.ttl.mu.Unlock()
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(options.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				d.sweepAll(options)
+			}
+		}
+	}()
+}
+
+func (d *Database) sweepAll(options SweeperOptions) {
+	d.ttl.mu.Lock()
+	d.ttl.sweeps++
+	d.ttl.mu.Unlock()
+	d.sweepTables(options.BatchSize, func(table string, _ int64, err error) {
+		if err != nil && options.OnError != nil {
+			options.OnError(table, err)
+		}
+	})
+}
+
+// StopSweeper stops the background sweeper, waiting for a sweep in progress to finish
+func (d *Database) StopSweeper() {
+	d.ttl.mu.Lock()
+	stop, done := d.ttl.stop, d.ttl.done
+	d.ttl.stop, d.ttl.done = nil, nil
+	d.ttl.mu.Unlock()
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+// TTLStats returns the number of background sweeps run and rows deleted by TTL
+func (d *Database) TTLStats() TTLStats {
+	d.ttl.mu.Lock()
+	defer d.ttl.mu.Unlock()
+	stats := TTLStats{Sweeps: d.ttl.sweeps, Reclaimed: make(map[string]uint64, len(d.ttl.reclaimed))}
+	for table, count := range d.ttl.reclaimed {
+		stats.Reclaimed[table] = count
+	}
+	return stats
+}
@@ -0,0 +1,70 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSweepExpired(t *testing.T) {
+	defer recovery(t)
+	_, err := tdb.Exec("CREATE TABLE IF NOT EXISTS sessions_ttl (id INT AUTO_INCREMENT PRIMARY KEY, created_at DATETIME NOT NULL)", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	_, err = tdb.Exec("DELETE FROM sessions_ttl", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	now := time.Date(2023, time.March, 1, 12, 0, 0, 0, time.UTC)
+	configs := getConfigs(false)
+	configs.Clock = func() time.Time { return now }
+	d, err := Make(configs)
+	if err != nil {
+		t.Error(err)
+	}
+	defer d.Close()
+	for _, age := range []time.Duration{time.Minute, 2 * time.Hour, 3 * time.Hour, 4 * time.Hour} {
+		_, err = d.MakeRecord(map[string]interface{}{"created_at": now.Add(-age)}, "sessions_ttl").Create()
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	d.SetTTL("sessions_ttl", "created_at", time.Hour)
+	deleted, err := d.SweepExpired(2)
+	if err != nil {
+		t.Error(err)
+	}
+	if deleted["sessions_ttl"] != 3 {
+		t.Errorf("expected 3 expired sessions to be deleted, got %d", deleted["sessions_ttl"])
+	}
+	if reclaimed := d.TTLStats().Reclaimed["sessions_ttl"]; reclaimed != 3 {
+		t.Errorf("expected 3 reclaimed rows, got %d", reclaimed)
+	}
+	rows, err := d.QueryRaw("select id from sessions_ttl", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(rows) != 1 {
+		t.Errorf("expected one session to be left, got %d", len(rows))
+	}
+}
+
+func TestSweeper(t *testing.T) {
+	defer recovery(t)
+	d, err := Make(getConfigs(false))
+	if err != nil {
+		t.Error(err)
+	}
+	defer d.Close()
+	d.StartSweeper(SweeperOptions{Interval: 10 * time.Millisecond})
+	time.Sleep(50 * time.Millisecond)
+	d.StopSweeper()
+	sweeps := d.TTLStats().Sweeps
+	if sweeps < 1 {
+		t.Errorf("expected the sweeper to have run")
+	}
+	time.Sleep(30 * time.Millisecond)
+	if d.TTLStats().Sweeps != sweeps {
+		t.Errorf("expected the sweeper to stop")
+	}
+}
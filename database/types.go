@@ -0,0 +1,344 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ScannerFactory makes a new scan destination for a column. It must return a pointer;
+// the value it points to once scanned is what appears in query results
+type ScannerFactory func() interface{}
+
+type typeRegistry struct {
+	mu       sync.RWMutex
+	mappings map[string]ScannerFactory
+	columns  map[string]ScannerFactory
+}
+
+var integerTypes = []string{"INT", "TINYINT", "BOOL", "BOOLEAN", "SMALLINT", "MEDIUMINT", "INTEGER", "BIGINT"}
+
+var boolTypes = []string{"TINYINT", "BOOL", "BOOLEAN"}
+
+var floatTypes = []string{"FLOAT", "DOUBLE"}
+
+var decimalTypes = []string{"DECIMAL", "DEC"}
+
+var stringTypes = []string{
+	"CHAR", "VARCHAR", "TINYTEXT", "TEXT", "MEDIUMTEXT", "LONGTEXT", "ENUM", "SET",
+}
+
+var binaryTypes = []string{"BINARY", "VARBINARY", "TINYBLOB", "BLOB", "MEDIUMBLOB", "LONGBLOB"}
+
+var temporalTypes = []string{"DATE", "DATETIME", "TIMESTAMP"}
+
+var legacyTemporalTypes = []string{"DATE", "DATETIME", "TIMESTAMP", "TIME", "YEAR"}
+
+// makes the registry of built-in type mappings for the configs
+func newTypeRegistry(configs *Configs) *typeRegistry {
+	registry := &typeRegistry{
+		mappings: make(map[string]ScannerFactory),
+		columns:  make(map[string]ScannerFactory),
+	}
+	registry.setAll(integerTypes, scanNullInt64)
+	registry.mappings["BIT"] = scanNullBit
+	registry.mappings["UNSIGNED BIGINT"] = scanNullUint64
+	registry.mappings["GEOMETRY"] = scanNullGeometry
+	if configs.TinyIntAsBool {
+		registry.setAll(boolTypes, scanNullBool)
+	}
+	registry.setAll(floatTypes, scanNullFloat64)
+	if configs.DecimalAsString {
+		registry.setAll(decimalTypes, scanNullString)
+	} else {
+		registry.setAll(decimalTypes, scanNullFloat64)
+	}
+	registry.setAll(stringTypes, scanNullString)
+	if configs.LegacyBinaryStrings {
+		registry.setAll(binaryTypes, scanNullString)
+	} else {
+		registry.setAll(binaryTypes, scanNullBytes)
+	}
+	if configs.DecodeJSON {
+		registry.mappings["JSON"] = scanNullJSON
+	}
+	if configs.LegacyTemporalStrings {
+		registry.setAll(legacyTemporalTypes, scanNullString)
+	} else {
+		registry.setAll(temporalTypes, scanNullTime)
+		registry.mappings["TIME"] = scanNullDuration
+		registry.mappings["YEAR"] = scanNullInt64
+	}
+	return registry
+}
+
+func (r *typeRegistry) setAll(typeNames []string, factory ScannerFactory) {
+	for _, typeName := range typeNames {
+		r.mappings[typeName] = factory
+	}
+}
+
+// finds the factory for a column, then for its type, falling back to strings for unknown types
+func (r *typeRegistry) factory(col string, typeName string) ScannerFactory {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if factory, ok := r.columns[col]; ok {
+		return factory
+	}
+	if factory, ok := r.mappings[strings.ToUpper(typeName)]; ok {
+		return factory
+	}
+	return scanNullString
+}
+
+// RegisterTypeMapping sets the scanner used for columns of a MySQL type, such as "POINT",
+// replacing any existing mapping for that type
+func (d *Database) RegisterTypeMapping(typeName string, factory ScannerFactory) {
+	d.types.mu.Lock()
+	defer d.types.mu.Unlock()
+	d.types.mappings[strings.ToUpper(typeName)] = factory
+}
+
+// RegisterColumnScanner hydrates result columns with the given name using a custom sql.Scanner,
+// such as an enum or encrypted string type, taking precedence over type mappings. Values of custom
+// types that implement driver.Valuer can be used as Record properties as they are
+func (d *Database) RegisterColumnScanner(column string, factory ScannerFactory) {
+	d.types.mu.Lock()
+	defer d.types.mu.Unlock()
+	d.types.columns[column] = factory
+}
+
+// makes a new row based on the database column type returned
+func (d *Database) makeRow(typeMapping map[string]string, cols []string) []interface{} {
+	row := make([]interface{}, 0)
+	for _, v := range cols {
+		row = append(row, d.types.factory(v, typeMapping[v])())
+	}
+	return row
+}
+
+// resultValue is implemented by scan destinations that convert their value for query results
+type resultValue interface {
+	result() (value interface{}, valid bool)
+}
+
+// nullBytes scans binary columns, copying the bytes out of the driver's buffer
+type nullBytes struct {
+	Bytes []byte
+	Valid bool
+}
+
+func (n *nullBytes) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case nil:
+		n.Bytes, n.Valid = nil, false
+	case []byte:
+		n.Bytes, n.Valid = append([]byte{}, value...), true
+	case string:
+		n.Bytes, n.Valid = []byte(value), true
+	default:
+		return fmt.Errorf("cannot scan %T into bytes", src)
+	}
+	return nil
+}
+
+func (n nullBytes) result() (interface{}, bool) {
+	return n.Bytes, n.Valid
+}
+
+// nullJSON scans JSON columns, decoding the document
+type nullJSON struct {
+	Value interface{}
+	Valid bool
+}
+
+func (n *nullJSON) Scan(src interface{}) error {
+	var raw []byte
+	switch value := src.(type) {
+	case nil:
+		n.Value, n.Valid = nil, false
+		return nil
+	case []byte:
+		raw = value
+	case string:
+		raw = []byte(value)
+	default:
+		return fmt.Errorf("cannot scan %T into JSON", src)
+	}
+	n.Valid = true
+	return json.Unmarshal(raw, &n.Value)
+}
+
+func (n nullJSON) result() (interface{}, bool) {
+	return n.Value, n.Valid
+}
+
+// nullBit scans BIT(n) columns, which the driver sends as big-endian bytes.
+// Values of up to 64 bits come back as uint64, anything wider as []byte
+type nullBit struct {
+	Value interface{}
+	Valid bool
+}
+
+func (n *nullBit) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case nil:
+		n.Value, n.Valid = uint64(0), false
+	case []byte:
+		n.Value, n.Valid = decodeBits(value), true
+	case int64:
+		n.Value, n.Valid = uint64(value), true
+	case uint64:
+		n.Value, n.Valid = value, true
+	default:
+		return fmt.Errorf("cannot scan %T into BIT", src)
+	}
+	return nil
+}
+
+func (n nullBit) result() (interface{}, bool) {
+	return n.Value, n.Valid
+}
+
+func decodeBits(raw []byte) interface{} {
+	if len(raw) > 8 {
+		return append([]byte{}, raw...)
+	}
+	var value uint64
+	for _, b := range raw {
+		value = value<<8 | uint64(b)
+	}
+	return value
+}
+
+// nullBool scans integer columns as bool, treating any non-zero value as true
+type nullBool struct {
+	Bool  bool
+	Valid bool
+}
+
+func (n *nullBool) Scan(src interface{}) error {
+	var value sql.NullInt64
+	err := value.Scan(src)
+	if err != nil {
+		return err
+	}
+	n.Bool, n.Valid = value.Int64 != 0, value.Valid
+	return nil
+}
+
+func (n nullBool) result() (interface{}, bool) {
+	return n.Bool, n.Valid
+}
+
+// nullUint64 scans BIGINT UNSIGNED columns, which can hold values above math.MaxInt64
+type nullUint64 struct {
+	Uint64 uint64
+	Valid  bool
+}
+
+func (n *nullUint64) Scan(src interface{}) error {
+	var err error
+	switch value := src.(type) {
+	case nil:
+		n.Uint64, n.Valid = 0, false
+	case uint64:
+		n.Uint64, n.Valid = value, true
+	case int64:
+		n.Uint64, n.Valid = uint64(value), true
+	case []byte:
+		n.Uint64, err = strconv.ParseUint(string(value), 10, 64)
+		n.Valid = err == nil
+	case string:
+		n.Uint64, err = strconv.ParseUint(value, 10, 64)
+		n.Valid = err == nil
+	default:
+		err = fmt.Errorf("cannot scan %T into uint64", src)
+	}
+	return err
+}
+
+func (n nullUint64) result() (interface{}, bool) {
+	return n.Uint64, n.Valid
+}
+
+// nullDuration scans TIME columns, which can be negative or longer than a day
+type nullDuration struct {
+	Duration time.Duration
+	Valid    bool
+}
+
+func (n *nullDuration) Scan(src interface{}) error {
+	var err error
+	switch value := src.(type) {
+	case nil:
+		n.Duration, n.Valid = 0, false
+	case []byte:
+		n.Duration, err = ParseTimeValue(string(value))
+		n.Valid = err == nil
+	case string:
+		n.Duration, err = ParseTimeValue(value)
+		n.Valid = err == nil
+	default:
+		err = fmt.Errorf("cannot scan %T into TIME", src)
+	}
+	return err
+}
+
+func (n nullDuration) result() (interface{}, bool) {
+	return n.Duration, n.Valid
+}
+
+func scanNullInt64() interface{} {
+	var newCol sql.NullInt64
+	return &newCol
+}
+
+func scanNullFloat64() interface{} {
+	var newCol sql.NullFloat64
+	return &newCol
+}
+
+func scanNullString() interface{} {
+	var newCol sql.NullString
+	return &newCol
+}
+
+func scanNullTime() interface{} {
+	var newCol sql.NullTime
+	return &newCol
+}
+
+func scanNullBytes() interface{} {
+	var newCol nullBytes
+	return &newCol
+}
+
+func scanNullJSON() interface{} {
+	var newCol nullJSON
+	return &newCol
+}
+
+func scanNullBit() interface{} {
+	var newCol nullBit
+	return &newCol
+}
+
+func scanNullBool() interface{} {
+	var newCol nullBool
+	return &newCol
+}
+
+func scanNullUint64() interface{} {
+	var newCol nullUint64
+	return &newCol
+}
+
+func scanNullDuration() interface{} {
+	var newCol nullDuration
+	return &newCol
+}
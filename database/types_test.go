@@ -0,0 +1,286 @@
+package database
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+type upperString string
+
+func (u *upperString) Scan(src interface{}) error {
+	var value sql.NullString
+	err := value.Scan(src)
+	if err != nil {
+		return err
+	}
+	*u = upperString("UPPER:" + value.String)
+	return nil
+}
+
+type rot13 []string
+
+func (r rot13) Value() (driver.Value, error) {
+	return rotate(strings.Join(r, ",")), nil
+}
+
+func (r *rot13) Scan(src interface{}) error {
+	var value sql.NullString
+	err := value.Scan(src)
+	if err != nil {
+		return err
+	}
+	*r = strings.Split(rotate(value.String), ",")
+	return nil
+}
+
+func rotate(value string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return 'a' + (r-'a'+13)%26
+		case r >= 'A' && r <= 'Z':
+			return 'A' + (r-'A'+13)%26
+		}
+		return r
+	}, value)
+}
+
+func TestRegisterColumnScanner(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	configs := getConfigs(false)
+	d, err := Make(configs)
+	if err != nil {
+		t.Error(err)
+	}
+	defer d.Close()
+	d.RegisterColumnScanner("description", func() interface{} {
+		var newCol rot13
+		return &newCol
+	})
+	id, err := d.MakeRecord(map[string]interface{}{
+		"sku":         "WIDG-ROT",
+		"description": rot13{"Secret", "Widget"},
+	}, "widgets").Create()
+	if err != nil {
+		t.Error(err)
+	}
+	raw, err := tdb.Row("select description from widgets where id = ?", id)
+	if err != nil {
+		t.Error(err)
+	}
+	if raw["description"] != "Frperg,Jvqtrg" {
+		t.Errorf("expected description to be stored through Value, got %v", raw["description"])
+	}
+	row, err := d.Row("select description from widgets where id = ?", id)
+	if err != nil {
+		t.Error(err)
+	}
+	if fmt.Sprint(row["description"]) != fmt.Sprint(rot13{"Secret", "Widget"}) {
+		t.Errorf("expected description to be hydrated through Scan, got %v", row["description"])
+	}
+}
+
+func TestRegisterTypeMapping(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	configs := getConfigs(false)
+	d, err := Make(configs)
+	if err != nil {
+		t.Error(err)
+	}
+	defer d.Close()
+	d.RegisterTypeMapping("varchar", func() interface{} {
+		var newCol upperString
+		return &newCol
+	})
+	row, err := d.Row("select sku from widgets where id = ?", 1)
+	if err != nil {
+		t.Error(err)
+	}
+	if row["sku"] != upperString("UPPER:WIDG1") {
+		t.Errorf("expected sku to be scanned by the registered mapping, got %v", row["sku"])
+	}
+	row, err = tdb.Row("select sku from widgets where id = ?", 1)
+	if err != nil {
+		t.Error(err)
+	}
+	if row["sku"] != "WIDG1" {
+		t.Errorf("expected other databases to keep the default mapping, got %v", row["sku"])
+	}
+}
+
+func TestBinaryColumns(t *testing.T) {
+	defer recovery(t)
+	binary := []byte{0x00, 0xff, 0x10, 0x80}
+	rows, err := tdb.QueryRaw("select unhex('00FF1080') as payload", nil)
+	if err != nil || len(rows) != 1 {
+		t.Fatalf("expected one row, got %v", err)
+	}
+	payload, ok := rows[0]["payload"].([]byte)
+	if !ok {
+		t.Errorf("expected payload to be []byte, got %T", rows[0]["payload"])
+	}
+	if !bytes.Equal(payload, binary) {
+		t.Errorf("expected payload to be %v, got %v", binary, payload)
+	}
+	configs := getConfigs(false)
+	configs.LegacyBinaryStrings = true
+	d, err := Make(configs)
+	if err != nil {
+		t.Error(err)
+	}
+	defer d.Close()
+	rows, err = d.QueryRaw("select unhex('00FF1080') as payload", nil)
+	if err != nil || len(rows) != 1 {
+		t.Fatalf("expected one row, got %v", err)
+	}
+	if payload, ok := rows[0]["payload"].(string); !ok || payload != string(binary) {
+		t.Errorf("expected payload to be a legacy string, got %T", rows[0]["payload"])
+	}
+}
+
+func TestJSONColumns(t *testing.T) {
+	defer recovery(t)
+	createDocumentsTable(t)
+	id, err := tdb.MakeRecord(map[string]interface{}{
+		"body": map[string]interface{}{
+			"tags":  []string{"a", "b"},
+			"title": "Document",
+		},
+	}, "documents").Create()
+	if err != nil {
+		t.Error(err)
+	}
+	row, err := tdb.Row("select body from documents where id = ?", id)
+	if err != nil {
+		t.Error(err)
+	}
+	if _, ok := row["body"].(string); !ok {
+		t.Errorf("expected body to be a string by default, got %T", row["body"])
+	}
+	configs := getConfigs(false)
+	configs.DecodeJSON = true
+	d, err := Make(configs)
+	if err != nil {
+		t.Error(err)
+	}
+	defer d.Close()
+	row, err = d.Row("select body from documents where id = ?", id)
+	if err != nil {
+		t.Error(err)
+	}
+	body, ok := row["body"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected body to be decoded into a map, got %T", row["body"])
+	}
+	if body["title"] != "Document" {
+		t.Errorf("expected title to be 'Document', got %v", body["title"])
+	}
+	if tags, ok := body["tags"].([]interface{}); !ok || len(tags) != 2 {
+		t.Errorf("expected tags to be decoded into a slice of 2, got %v", body["tags"])
+	}
+}
+
+func TestDecimalAsString(t *testing.T) {
+	defer recovery(t)
+	query := "select cast('12345678901234567.89' as decimal(20, 2)) as price"
+	rows, err := tdb.QueryRaw(query, nil)
+	if err != nil || len(rows) != 1 {
+		t.Fatalf("expected one row, got %v", err)
+	}
+	if _, ok := rows[0]["price"].(float64); !ok {
+		t.Errorf("expected price to be a float64 by default, got %T", rows[0]["price"])
+	}
+	configs := getConfigs(false)
+	configs.DecimalAsString = true
+	d, err := Make(configs)
+	if err != nil {
+		t.Error(err)
+	}
+	defer d.Close()
+	rows, err = d.QueryRaw(query, nil)
+	if err != nil || len(rows) != 1 {
+		t.Fatalf("expected one row, got %v", err)
+	}
+	if rows[0]["price"] != "12345678901234567.89" {
+		t.Errorf("expected price to be '12345678901234567.89', got %v", rows[0]["price"])
+	}
+}
+
+func TestBitColumns(t *testing.T) {
+	defer recovery(t)
+	_, err := tdb.Exec("CREATE TABLE IF NOT EXISTS flags (id INT AUTO_INCREMENT PRIMARY KEY, enabled BIT(1), mask BIT(12))", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	result, err := tdb.Exec("INSERT INTO flags (enabled, mask) VALUES (b'1', b'101000000001')", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		t.Error(err)
+	}
+	row, err := tdb.Row("select enabled, mask from flags where id = ?", id)
+	if err != nil {
+		t.Error(err)
+	}
+	if row["enabled"] != uint64(1) {
+		t.Errorf("expected enabled to be 1, got %v (%T)", row["enabled"], row["enabled"])
+	}
+	if row["mask"] != uint64(0xa01) {
+		t.Errorf("expected mask to be %d, got %v (%T)", 0xa01, row["mask"], row["mask"])
+	}
+}
+
+func TestTinyIntAsBool(t *testing.T) {
+	defer recovery(t)
+	_, err := tdb.Exec("CREATE TABLE IF NOT EXISTS toggles (id INT AUTO_INCREMENT PRIMARY KEY, active BOOL NOT NULL)", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	id, err := tdb.MakeRecord(map[string]interface{}{
+		"active": true,
+	}, "toggles").Create()
+	if err != nil {
+		t.Error(err)
+	}
+	row, err := tdb.Row("select active from toggles where id = ?", id)
+	if err != nil {
+		t.Error(err)
+	}
+	if row["active"] != int64(1) {
+		t.Errorf("expected active to be 1 by default, got %v (%T)", row["active"], row["active"])
+	}
+	configs := getConfigs(false)
+	configs.TinyIntAsBool = true
+	d, err := Make(configs)
+	if err != nil {
+		t.Error(err)
+	}
+	defer d.Close()
+	row, err = d.Row("select active from toggles where id = ?", id)
+	if err != nil {
+		t.Error(err)
+	}
+	if row["active"] != true {
+		t.Errorf("expected active to be true, got %v (%T)", row["active"], row["active"])
+	}
+}
+
+func TestUnsignedBigInt(t *testing.T) {
+	defer recovery(t)
+	rows, err := tdb.QueryRaw("select cast(18446744073709551615 as unsigned) as big", nil)
+	if err != nil || len(rows) != 1 {
+		t.Fatalf("expected one row, got %v", err)
+	}
+	if rows[0]["big"] != uint64(math.MaxUint64) {
+		t.Errorf("expected big to be %d, got %v (%T)", uint64(math.MaxUint64), rows[0]["big"], rows[0]["big"])
+	}
+}
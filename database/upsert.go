@@ -0,0 +1,143 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// FirstOrCreate returns the row of a table matching every column in match, inserting match and create
+// together when there is none. The insert leaves an existing row alone rather than checking for one
+// first, so concurrent callers get the same row as long as match covers a unique index
+func (d *Database) FirstOrCreate(table string, match, create map[string]interface{}) (*Record, error) {
+	record, err := d.findBy(table, match)
+	if !errors.Is(err, ErrNoResult) {
+		return record, err
+	}
+	attributes := mergeAttributes(create, match)
+	err = d.upsert(table, attributes, nil)
+	if err != nil {
+		return nil, err
+	}
+	return d.findBy(table, match)
+}
+
+// UpdateOrCreate sets values on the row of a table matching every column in match, inserting the row
+// when there is none, as one INSERT ... ON DUPLICATE KEY UPDATE. Like FirstOrCreate, match must cover
+// a unique index for concurrent callers to share a row
+func (d *Database) UpdateOrCreate(table string, match, values map[string]interface{}) (*Record, error) {
+	attributes := mergeAttributes(values, match)
+	update := make([]string, 0, len(values))
+	for _, column := range d.MakeRecord(values, table).fields() {
+		if _, ok := match[column]; !ok {
+			update = append(update, column)
+		}
+	}
+	err := d.upsert(table, attributes, update)
+	if err != nil {
+		return nil, err
+	}
+	return d.findBy(table, match)
+}
+
+func (d *Database) findBy(table string, match map[string]interface{}) (*Record, error) {
+	return d.FindRecordBy(table, mergeAttributes(match, nil))
+}
+
+// inserts a row, updating the given columns of the row it collides with instead. With no columns
+// to update, the existing row is left as it is. The row goes through Create's pipeline of guards,
+// defaults, timestamps, casts and hooks; the AfterCreate hooks run only when a row was inserted
+func (d *Database) upsert(table string, attributes map[string]interface{}, update []string) error {
+	if len(attributes) < 1 {
+		return errors.New("no columns to insert")
+	}
+	record := d.MakeRecord(attributes, table)
+	err := record.beforeCreate()
+	if err != nil {
+		return err
+	}
+	if len(record.properties) < 1 {
+		return errors.New("no columns to insert")
+	}
+	insertStatement, inserts, err := record.insertStatement()
+	if err != nil {
+		return err
+	}
+	version, err := d.ServerVersion()
+	if err != nil {
+		return err
+	}
+	result, err := d.ExecWithOptions(
+		insertStatement+record.onDuplicateKeyUpdate(update, supportsRowAlias(version)),
+		inserts,
+		record.options(),
+	)
+	if err != nil {
+		return err
+	}
+	// MySQL counts an inserted row as 1 and an updated one as 2
+	affected, err := result.RowsAffected()
+	if err != nil || affected != 1 {
+		return err
+	}
+	record.markClean()
+	return record.runHooks(AfterCreate)
+}
+
+// builds the ON DUPLICATE KEY UPDATE clause for the columns the record still has after its guards,
+// touching updated_at along with them. Servers with row aliases read the new values from the alias,
+// others from VALUES(), which MySQL 8.0.20 deprecates
+func (r *Record) onDuplicateKeyUpdate(update []string, rowAlias bool) string {
+	columns := make([]string, 0, len(update)+1)
+	for _, column := range update {
+		if _, ok := r.properties[column]; ok && !r.omitted(r.properties[column]) {
+			columns = append(columns, column)
+		}
+	}
+	if _, ok := r.properties["updated_at"]; ok && len(columns) > 0 && r.database.timestamped(r.table) && !contains(columns, "updated_at") {
+		columns = append(columns, "updated_at")
+	}
+	assignments := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if rowAlias {
+			assignments = append(assignments, quoteIdentifier(column)+" = `new`."+quoteIdentifier(column))
+			continue
+		}
+		assignments = append(assignments, quoteIdentifier(column)+" = VALUES("+quoteIdentifier(column)+")")
+	}
+	if len(assignments) < 1 {
+		// a no-op assignment leaves the existing row alone
+		first := quoteIdentifier(r.fields()[0])
+		assignments = append(assignments, first+" = "+first)
+	}
+	clause := " ON DUPLICATE KEY UPDATE " + strings.Join(assignments, ", ")
+	if rowAlias {
+		return " AS `new`" + clause
+	}
+	return clause
+}
+
+// reports whether the server takes a row alias on INSERT, as MySQL does from 8.0.19 and MariaDB doesn't
+func supportsRowAlias(version string) bool {
+	if isMariaDBVersion(version) {
+		return false
+	}
+	var major, minor, patch int
+	fmt.Sscanf(version, "%d.%d.%d", &major, &minor, &patch)
+	if major != 8 {
+		return major > 8
+	}
+	return minor > 0 || patch >= 19
+}
+
+// copies attributes, with those in overrides taking precedence
+func mergeAttributes(attributes, overrides map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(attributes)+len(overrides))
+	for column, value := range attributes {
+		merged[column] = value
+	}
+	for column, value := range overrides {
+		merged[column] = value
+	}
+	return merged
+}
@@ -0,0 +1,140 @@
+package database
+
+import (
+	"sync"
+	"testing"
+)
+
+func createSubscriptionsTable(t *testing.T) {
+	_, err := tdb.Exec("CREATE TABLE IF NOT EXISTS subscriptions (id INT AUTO_INCREMENT PRIMARY KEY, email VARCHAR(100) NOT NULL UNIQUE, plan VARCHAR(20))", nil)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestFirstOrCreate(t *testing.T) {
+	defer recovery(t)
+	createSubscriptionsTable(t)
+	match := map[string]interface{}{"email": "first@example.com"}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := tdb.FirstOrCreate("subscriptions", match, map[string]interface{}{"plan": "free"}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	rows, err := tdb.QueryRaw("select * from subscriptions where email = ?", []interface{}{"first@example.com"})
+	if err != nil {
+		t.Error(err)
+	}
+	if len(rows) != 1 {
+		t.Errorf("expected one subscription, got %d", len(rows))
+	}
+	record, err := tdb.FirstOrCreate("subscriptions", match, map[string]interface{}{"plan": "gold"})
+	if err != nil {
+		t.Error(err)
+	}
+	if plan, _ := record.GetString("plan"); plan != "free" {
+		t.Errorf("expected the existing subscription to be returned unchanged, got %s", plan)
+	}
+}
+
+func TestUpdateOrCreate(t *testing.T) {
+	defer recovery(t)
+	createSubscriptionsTable(t)
+	match := map[string]interface{}{"email": "update@example.com"}
+	for _, plan := range []string{"free", "gold"} {
+		record, err := tdb.UpdateOrCreate("subscriptions", match, map[string]interface{}{"plan": plan})
+		if err != nil {
+			t.Error(err)
+		}
+		if value, _ := record.GetString("plan"); value != plan {
+			t.Errorf("expected the plan to be %s, got %s", plan, value)
+		}
+	}
+	rows, err := tdb.QueryRaw("select * from subscriptions where email = ?", []interface{}{"update@example.com"})
+	if err != nil {
+		t.Error(err)
+	}
+	if len(rows) != 1 {
+		t.Errorf("expected one subscription, got %d", len(rows))
+	}
+}
+
+func TestOnDuplicateKeyUpdate(t *testing.T) {
+	d := &Database{configs: &Configs{Timestamps: []string{"subscriptions"}}}
+	record := d.MakeRecord(map[string]interface{}{"email": "a@example.com", "plan": "gold", "updated_at": "now"}, "subscriptions")
+	clause := record.onDuplicateKeyUpdate([]string{"plan", "guarded"}, true)
+	if clause != " AS `new` ON DUPLICATE KEY UPDATE `plan` = `new`.`plan`, `updated_at` = `new`.`updated_at`" {
+		t.Errorf("unexpected row alias clause %s", clause)
+	}
+	clause = record.onDuplicateKeyUpdate([]string{"plan"}, false)
+	if clause != " ON DUPLICATE KEY UPDATE `plan` = VALUES(`plan`), `updated_at` = VALUES(`updated_at`)" {
+		t.Errorf("unexpected VALUES() clause %s", clause)
+	}
+	clause = record.onDuplicateKeyUpdate(nil, true)
+	if clause != " AS `new` ON DUPLICATE KEY UPDATE `email` = `email`" {
+		t.Errorf("expected an existing row to be left alone, got %s", clause)
+	}
+}
+
+func TestSupportsRowAlias(t *testing.T) {
+	for version, supported := range map[string]bool{
+		"8.0.32":                    true,
+		"8.0.19":                    true,
+		"8.0.18":                    false,
+		"8.4.0":                     true,
+		"9.1.0":                     true,
+		"5.7.44-log":                false,
+		"10.11.2-MariaDB-1:10.11.2": false,
+	} {
+		if supportsRowAlias(version) != supported {
+			t.Errorf("expected row alias support for %s to be %v", version, supported)
+		}
+	}
+}
+
+func TestUpsertRecordPipeline(t *testing.T) {
+	defer recovery(t)
+	createSubscriptionsTable(t)
+	configs := getConfigs(false)
+	configs.Guarded = map[string][]string{"subscriptions": {"id"}}
+	configs.StrictAssignment = true
+	d, err := Make(configs)
+	if err != nil {
+		t.Error(err)
+	}
+	defer d.Close()
+	_, err = d.UpdateOrCreate("subscriptions", map[string]interface{}{"email": "guarded@example.com"}, map[string]interface{}{"id": 999})
+	if err == nil {
+		t.Errorf("expected the upsert to reject a guarded column")
+	}
+	var created int
+	d.RegisterHook("subscriptions", BeforeCreate, func(record *Record) error {
+		record.Set("plan", "trial")
+		return nil
+	})
+	d.RegisterHook("subscriptions", AfterCreate, func(record *Record) error {
+		created++
+		return nil
+	})
+	match := map[string]interface{}{"email": "hooks@example.com"}
+	record, err := d.FirstOrCreate("subscriptions", match, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan, _ := record.GetString("plan"); plan != "trial" {
+		t.Errorf("expected the BeforeCreate hook to set the plan, got %s", plan)
+	}
+	err = d.upsert("subscriptions", match, nil)
+	if err != nil {
+		t.Error(err)
+	}
+	if created != 1 {
+		t.Errorf("expected AfterCreate to run only for the insert, ran %d times", created)
+	}
+}
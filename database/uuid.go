@@ -0,0 +1,141 @@
+package database
+
+import (
+	"crypto/rand"
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// UUID is a universally unique identifier, stored as CHAR(36) or BINARY(16) depending on Configs.UUIDBinary
+type UUID [16]byte
+
+// NewUUID generates a random (version 4) UUID
+func NewUUID() (UUID, error) {
+	var uuid UUID
+	_, err := rand.Read(uuid[:])
+	if err != nil {
+		return uuid, err
+	}
+	uuid[6] = (uuid[6] & 0x0f) | 0x40
+	uuid[8] = (uuid[8] & 0x3f) | 0x80
+	return uuid, nil
+}
+
+// ParseUUID parses a UUID from its 36 character form, with or without hyphens
+func ParseUUID(value string) (UUID, error) {
+	var uuid UUID
+	raw := strings.ReplaceAll(value, "-", "")
+	if len(raw) != 32 {
+		return uuid, fmt.Errorf("invalid UUID %q", value)
+	}
+	_, err := hex.Decode(uuid[:], []byte(raw))
+	if err != nil {
+		return uuid, fmt.Errorf("invalid UUID %q", value)
+	}
+	return uuid, nil
+}
+
+// String returns the UUID in its 36 character form
+func (u UUID) String() string {
+	encoded := hex.EncodeToString(u[:])
+	return encoded[:8] + "-" + encoded[8:12] + "-" + encoded[12:16] + "-" + encoded[16:20] + "-" + encoded[20:]
+}
+
+// MarshalText encodes the UUID in its canonical form, so it appears as a string in JSON
+func (u UUID) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+// UnmarshalText parses a UUID in its canonical form
+func (u *UUID) UnmarshalText(text []byte) error {
+	parsed, err := ParseUUID(string(text))
+	if err != nil {
+		return err
+	}
+	*u = parsed
+	return nil
+}
+
+// Value binds the UUID in its 36 character form when it is passed straight to a query; records and
+// inserts bind it as BINARY(16) instead when Configs.UUIDBinary is set
+func (u UUID) Value() (driver.Value, error) {
+	return u.String(), nil
+}
+
+// Bytes returns the UUID's 16 bytes
+func (u UUID) Bytes() []byte {
+	return append([]byte{}, u[:]...)
+}
+
+// GenerateUUID makes Create set a new UUID on the field (usually the primary key) when it has no value
+func (r *Record) GenerateUUID(field string) *Record {
+	if r.properties == nil {
+		r.properties = make(map[string]interface{})
+	}
+	r.uuidField = field
+	return r
+}
+
+// Property returns a property of the record
+func (r *Record) Property(field string) (interface{}, bool) {
+	value, ok := r.properties[field]
+	return value, ok
+}
+
+func (r *Record) generateUUID() error {
+	if len(r.uuidField) < 1 || r.properties[r.uuidField] != nil {
+		return nil
+	}
+	uuid, err := NewUUID()
+	if err != nil {
+		return err
+	}
+	r.properties[r.uuidField] = uuid
+	return nil
+}
+
+func (d *Database) bindUUID(uuid UUID) interface{} {
+	if d.configs.UUIDBinary {
+		return uuid.Bytes()
+	}
+	return uuid.String()
+}
+
+func (d *Database) isUUIDColumn(col string) bool {
+	for _, uuidCol := range d.configs.UUIDColumns {
+		if uuidCol == col {
+			return true
+		}
+	}
+	return false
+}
+
+// converts a CHAR(36) or BINARY(16) value into a UUID, leaving values that are not UUIDs as they are.
+// Only bytes are read as a BINARY(16) UUID, or a string from a binary column when LegacyBinaryStrings
+// returns those as strings, so 16 characters of text are left alone
+func toUUID(value interface{}, binary bool) interface{} {
+	var uuid UUID
+	switch typed := value.(type) {
+	case []byte:
+		if len(typed) == 16 {
+			copy(uuid[:], typed)
+			return uuid
+		}
+		parsed, err := ParseUUID(string(typed))
+		if err == nil {
+			return parsed
+		}
+	case string:
+		if binary && len(typed) == 16 {
+			copy(uuid[:], typed)
+			return uuid
+		}
+		parsed, err := ParseUUID(typed)
+		if err == nil {
+			return parsed
+		}
+	}
+	return value
+}
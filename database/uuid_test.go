@@ -0,0 +1,150 @@
+package database
+
+import (
+	"testing"
+)
+
+func TestUUIDColumns(t *testing.T) {
+	defer recovery(t)
+	_, err := tdb.Exec("CREATE TABLE IF NOT EXISTS tokens (id CHAR(36) PRIMARY KEY, secret BINARY(16), label VARCHAR(100))", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	secret, err := NewUUID()
+	if err != nil {
+		t.Error(err)
+	}
+	configs := getConfigs(false)
+	configs.UUIDColumns = []string{"id", "secret"}
+	d, err := Make(configs)
+	if err != nil {
+		t.Error(err)
+	}
+	defer d.Close()
+	record := d.MakeRecord(map[string]interface{}{
+		"secret": secret.Bytes(),
+		"label":  "token",
+	}, "tokens").GenerateUUID("id")
+	_, err = record.Create()
+	if err != nil {
+		t.Error(err)
+	}
+	id, ok := record.Property("id")
+	if !ok {
+		t.Fatalf("expected an id to be generated")
+	}
+	rows, err := d.QueryRaw("select id, secret, label from tokens where id = ?", []interface{}{id})
+	if err != nil || len(rows) != 1 {
+		t.Fatalf("expected one row, got %v", err)
+	}
+	if rows[0]["id"] != id {
+		t.Errorf("expected id to be %v, got %v", id, rows[0]["id"])
+	}
+	if rows[0]["secret"] != secret {
+		t.Errorf("expected secret to be %v, got %v", secret, rows[0]["secret"])
+	}
+	if rows[0]["label"] != "token" {
+		t.Errorf("expected label to be left alone, got %v", rows[0]["label"])
+	}
+}
+
+func TestUUIDKeys(t *testing.T) {
+	defer recovery(t)
+	_, err := tdb.Exec("CREATE TABLE IF NOT EXISTS tokens (id CHAR(36) PRIMARY KEY, secret BINARY(16), label VARCHAR(100))", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	for _, binary := range []bool{false, true} {
+		table := "tokens"
+		if binary {
+			table = "binary_tokens"
+			_, err = tdb.Exec("CREATE TABLE IF NOT EXISTS binary_tokens (id BINARY(16) PRIMARY KEY, label VARCHAR(100))", nil)
+			if err != nil {
+				t.Error(err)
+			}
+		}
+		configs := getConfigs(false)
+		configs.UUIDColumns = []string{"id"}
+		configs.UUIDBinary = binary
+		d, err := Make(configs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		record := d.MakeRecord(map[string]interface{}{"label": "before"}, table).GenerateUUID("id")
+		_, err = record.Create()
+		if err != nil {
+			t.Fatal(err)
+		}
+		id, _ := record.Property("id")
+		affected, err := record.Set("label", "after").Update("id")
+		if err != nil || affected != 1 {
+			t.Errorf("expected the record to be updated by its UUID, got %d %v", affected, err)
+		}
+		exists, err := record.Exists()
+		if err != nil || !exists {
+			t.Errorf("expected the record to exist by its UUID, got %v %v", exists, err)
+		}
+		found, err := d.FindRecord(table, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if label, _ := found.Property("label"); label != "after" {
+			t.Errorf("expected the label to be updated, got %v", label)
+		}
+		if foundID, _ := found.Property("id"); foundID != id {
+			t.Errorf("expected id %v, got %v", id, foundID)
+		}
+		d.Close()
+	}
+}
+
+func TestParseUUID(t *testing.T) {
+	uuid, err := NewUUID()
+	if err != nil {
+		t.Error(err)
+	}
+	parsed, err := ParseUUID(uuid.String())
+	if err != nil {
+		t.Error(err)
+	}
+	if parsed != uuid {
+		t.Errorf("expected %s, got %s", uuid, parsed)
+	}
+	if uuid.String()[14] != '4' {
+		t.Errorf("expected a version 4 UUID, got %s", uuid)
+	}
+	_, err = ParseUUID("not-a-uuid")
+	if err == nil {
+		t.Errorf("expected an error for an invalid UUID")
+	}
+}
+
+func TestToUUID(t *testing.T) {
+	uuid, err := NewUUID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if toUUID(uuid.Bytes(), true) != uuid {
+		t.Errorf("expected 16 bytes to be read as a binary UUID")
+	}
+	if toUUID(uuid.String(), false) != uuid {
+		t.Errorf("expected CHAR(36) text to be parsed")
+	}
+	if value := toUUID("sixteen chars!!!", false); value != "sixteen chars!!!" {
+		t.Errorf("expected 16 characters of text to be left alone, got %v", value)
+	}
+	if toUUID(string(uuid.Bytes()), true) != uuid {
+		t.Errorf("expected a legacy binary string to be read as a binary UUID")
+	}
+}
+
+func TestGenerateUUIDWithoutProperties(t *testing.T) {
+	d := &Database{configs: &Configs{}}
+	record := d.MakeRecord(nil, "tokens").GenerateUUID("id")
+	if err := record.generateUUID(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := record.Property("id"); !ok {
+		t.Errorf("expected a UUID to be generated")
+	}
+}
@@ -0,0 +1,201 @@
+package database
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Vector is an embedding. As a query argument or Record property it is written as little-endian
+// float32s, the layout of BLOB-encoded vectors and of MySQL's native VECTOR type. Use JSON to
+// write it to a JSON column
+type Vector []float32
+
+// VectorMetric is how NearestNeighbors measures the distance between vectors; smaller is nearer
+type VectorMetric int
+
+const (
+	// CosineDistance is one minus the cosine similarity
+	CosineDistance VectorMetric = iota
+	// DotProductDistance is the negated dot product, for normalized embeddings
+	DotProductDistance
+)
+
+// Value encodes the vector as little-endian float32s
+func (v Vector) Value() (driver.Value, error) {
+	encoded := make([]byte, 4*len(v))
+	for i, component := range v {
+		binary.LittleEndian.PutUint32(encoded[4*i:], math.Float32bits(component))
+	}
+	return encoded, nil
+}
+
+// JSON encodes the vector as a JSON array, for storing in JSON columns
+func (v Vector) JSON() string {
+	components := make([]string, len(v))
+	for i, component := range v {
+		components[i] = strconv.FormatFloat(float64(component), 'g', -1, 32)
+	}
+	return "[" + strings.Join(components, ",") + "]"
+}
+
+// ParseVector decodes a vector read as bytes from a BLOB or native VECTOR column, or read as a string
+// or decoded document from a JSON column
+func ParseVector(value interface{}) (Vector, error) {
+	switch stored := value.(type) {
+	case Vector:
+		return stored, nil
+	case []interface{}:
+		encoded, err := json.Marshal(stored)
+		if err != nil {
+			return nil, err
+		}
+		return parseVectorJSON(encoded)
+	case string:
+		return parseVectorJSON([]byte(stored))
+	case []byte:
+		if len(stored)%4 != 0 {
+			return nil, fmt.Errorf("a vector of %d bytes is not a whole number of float32s", len(stored))
+		}
+		vector := make(Vector, len(stored)/4)
+		for i := range vector {
+			vector[i] = math.Float32frombits(binary.LittleEndian.Uint32(stored[4*i:]))
+		}
+		return vector, nil
+	}
+	return nil, fmt.Errorf("cannot parse %T as a vector", value)
+}
+
+func parseVectorJSON(encoded []byte) (Vector, error) {
+	var vector Vector
+	err := json.Unmarshal(encoded, &vector)
+	return vector, err
+}
+
+// Distance measures the distance between two vectors of the same length
+func (v Vector) Distance(other Vector, metric VectorMetric) (float64, error) {
+	if len(v) != len(other) {
+		return 0, fmt.Errorf("cannot compare vectors of %d and %d dimensions", len(v), len(other))
+	}
+	var dot, norm, otherNorm float64
+	for i := range v {
+		dot += float64(v[i]) * float64(other[i])
+		norm += float64(v[i]) * float64(v[i])
+		otherNorm += float64(other[i]) * float64(other[i])
+	}
+	if metric == DotProductDistance {
+		return -dot, nil
+	}
+	if norm == 0 || otherNorm == 0 {
+		return 0, errors.New("cannot take the cosine distance of a zero vector")
+	}
+	return 1 - dot/(math.Sqrt(norm)*math.Sqrt(otherNorm)), nil
+}
+
+// DistanceExpression generates a SQL expression for the distance between the vectors stored as JSON
+// arrays in a column and the given vector, with its bound values. Stored vectors of another length
+// have a NULL distance
+func DistanceExpression(column string, vector Vector, metric VectorMetric) (string, []interface{}) {
+	var products, squares []string
+	var args []interface{}
+	var norm float64
+	for i, component := range vector {
+		element := fmt.Sprintf("JSON_EXTRACT(%s, '$[%d]')", quoteIdentifier(column), i)
+		products = append(products, element+" * ?")
+		squares = append(squares, element+" * "+element)
+		args = append(args, float64(component))
+		norm += float64(component) * float64(component)
+	}
+	length := fmt.Sprintf("JSON_LENGTH(%s) = %d", quoteIdentifier(column), len(vector))
+	dot := "(" + strings.Join(products, " + ") + ")"
+	if metric == DotProductDistance {
+		return "IF(" + length + ", -" + dot + ", NULL)", args
+	}
+	args = append(args, math.Sqrt(norm))
+	return "IF(" + length + ", 1 - " + dot + " / (SQRT(" + strings.Join(squares, " + ") + ") * ?), NULL)", args
+}
+
+// NearestNeighbors returns the k rows of a table whose vectors in the column are nearest the given
+// vector, nearest first, each with its distance in a "distance" column. JSON columns are ranked by
+// the database, as are MariaDB VECTOR columns by cosine distance; other columns, such as BLOBs or MySQL
+// VECTOR columns, are read in full and ranked here, so suit small tables
+func (d *Database) NearestNeighbors(table, column string, vector Vector, k int, metric VectorMetric) ([]map[string]interface{}, error) {
+	if len(vector) < 1 || k < 1 {
+		return nil, errors.New("nearest neighbors need a vector and a number of rows")
+	}
+	dataType, err := d.columnDataType(table, column)
+	if err != nil {
+		return nil, err
+	}
+	from := quoteIdentifier(d.Name()) + "." + quoteIdentifier(table)
+	switch dataType {
+	case "json", "longtext":
+		expression, args := DistanceExpression(column, vector, metric)
+		return d.QueryRaw(
+			"SELECT *, "+expression+" AS distance FROM "+from+" ORDER BY distance IS NULL, distance LIMIT "+strconv.Itoa(k),
+			args,
+		)
+	case "vector":
+		mariaDB, err := d.IsMariaDB()
+		if err != nil {
+			return nil, err
+		}
+		if mariaDB && metric == CosineDistance {
+			return d.QueryRaw(
+				"SELECT *, VEC_DISTANCE_COSINE("+quoteIdentifier(column)+", VEC_FromText(?)) AS distance FROM "+from+
+					" ORDER BY distance LIMIT "+strconv.Itoa(k),
+				[]interface{}{vector.JSON()},
+			)
+		}
+	}
+	rows, err := d.QueryRaw("SELECT * FROM "+from+" WHERE "+quoteIdentifier(column)+" IS NOT NULL", nil)
+	if err != nil {
+		return nil, err
+	}
+	return nearestRows(rows, column, vector, k, metric)
+}
+
+// ranks rows by the distance of their vectors, skipping those of another length
+func nearestRows(rows []map[string]interface{}, column string, vector Vector, k int, metric VectorMetric) ([]map[string]interface{}, error) {
+	ranked := make([]map[string]interface{}, 0, len(rows))
+	for _, row := range rows {
+		stored, err := ParseVector(row[column])
+		if err != nil {
+			return nil, err
+		}
+		distance, err := vector.Distance(stored, metric)
+		if err != nil {
+			continue
+		}
+		row["distance"] = distance
+		ranked = append(ranked, row)
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i]["distance"].(float64) < ranked[j]["distance"].(float64)
+	})
+	if len(ranked) > k {
+		ranked = ranked[:k]
+	}
+	return ranked, nil
+}
+
+func (d *Database) columnDataType(table, column string) (string, error) {
+	rows, err := d.QueryRaw(
+		"SELECT data_type AS data_type FROM information_schema.columns WHERE table_schema = ? AND table_name = ? AND column_name = ?",
+		[]interface{}{d.Name(), table, column},
+	)
+	if err != nil {
+		return "", err
+	}
+	if len(rows) < 1 {
+		return "", fmt.Errorf("column %s.%s not found", table, column)
+	}
+	dataType, _ := rows[0]["data_type"].(string)
+	return strings.ToLower(dataType), nil
+}
@@ -0,0 +1,112 @@
+package database
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestVectorEncoding(t *testing.T) {
+	vector := Vector{1, -0.5, 0.25}
+	encoded, err := vector.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := ParseVector(encoded)
+	if err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual(decoded, vector) {
+		t.Errorf("expected the binary encoding to round trip, got %v", decoded)
+	}
+	if vector.JSON() != "[1,-0.5,0.25]" {
+		t.Errorf("unexpected JSON %s", vector.JSON())
+	}
+	decoded, err = ParseVector(vector.JSON())
+	if err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual(decoded, vector) {
+		t.Errorf("expected the JSON encoding to round trip, got %v", decoded)
+	}
+	if _, err = ParseVector([]byte{1, 2, 3}); err == nil {
+		t.Errorf("expected a partial float32 to fail to parse")
+	}
+}
+
+func TestVectorDistance(t *testing.T) {
+	distance, err := Vector{1, 0}.Distance(Vector{0, 1}, CosineDistance)
+	if err != nil || math.Abs(distance-1) > 1e-9 {
+		t.Errorf("expected orthogonal vectors to have a cosine distance of 1, got %v %v", distance, err)
+	}
+	distance, err = Vector{1, 2}.Distance(Vector{3, 4}, DotProductDistance)
+	if err != nil || distance != -11 {
+		t.Errorf("expected a dot product distance of -11, got %v %v", distance, err)
+	}
+	if _, err = (Vector{1}).Distance(Vector{1, 2}, CosineDistance); err == nil {
+		t.Errorf("expected vectors of different lengths not to compare")
+	}
+}
+
+func TestDistanceExpression(t *testing.T) {
+	expression, args := DistanceExpression("embedding", Vector{3, 4}, CosineDistance)
+	expected := "IF(JSON_LENGTH(`embedding`) = 2, 1 - (JSON_EXTRACT(`embedding`, '$[0]') * ? + JSON_EXTRACT(`embedding`, '$[1]') * ?) / " +
+		"(SQRT(JSON_EXTRACT(`embedding`, '$[0]') * JSON_EXTRACT(`embedding`, '$[0]') + JSON_EXTRACT(`embedding`, '$[1]') * JSON_EXTRACT(`embedding`, '$[1]')) * ?), NULL)"
+	if expression != expected {
+		t.Errorf("unexpected expression %s", expression)
+	}
+	if !reflect.DeepEqual(args, []interface{}{3.0, 4.0, 5.0}) {
+		t.Errorf("unexpected args %v", args)
+	}
+}
+
+func TestNearestRows(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"id": 1, "embedding": mustEncode(t, Vector{0, 1})},
+		{"id": 2, "embedding": mustEncode(t, Vector{1, 0.1})},
+		{"id": 3, "embedding": mustEncode(t, Vector{1, 0, 0})},
+		{"id": 4, "embedding": mustEncode(t, Vector{1, 1})},
+	}
+	nearest, err := nearestRows(rows, "embedding", Vector{1, 0}, 2, CosineDistance)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nearest) != 2 || nearest[0]["id"] != 2 || nearest[1]["id"] != 4 {
+		t.Errorf("unexpected neighbors %v", nearest)
+	}
+}
+
+func mustEncode(t *testing.T, vector Vector) []byte {
+	encoded, err := vector.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return encoded.([]byte)
+}
+
+func TestNearestNeighbors(t *testing.T) {
+	defer recovery(t)
+	_, err := tdb.Exec("CREATE TABLE IF NOT EXISTS embeddings (id INT AUTO_INCREMENT PRIMARY KEY, label VARCHAR(20), vector_json JSON, vector_blob BLOB)", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	_, err = tdb.Exec("DELETE FROM embeddings", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	for label, vector := range map[string]Vector{"north": {0, 1}, "east": {1, 0}, "northeast": {1, 1}} {
+		_, err = tdb.MakeRecord(map[string]interface{}{"label": label, "vector_json": vector.JSON(), "vector_blob": vector}, "embeddings").Create()
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	for _, column := range []string{"vector_json", "vector_blob"} {
+		nearest, err := tdb.NearestNeighbors("embeddings", column, Vector{0.9, 0.1}, 2, CosineDistance)
+		if err != nil {
+			t.Error(err)
+		}
+		if len(nearest) != 2 || nearest[0]["label"] != "east" || nearest[1]["label"] != "northeast" {
+			t.Errorf("unexpected neighbors by %s: %v", column, nearest)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package database
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"sync"
+)
+
+// Divergence is a sampled row that differs between a DualWrite handle's databases.
+// A row missing from one side is nil there
+type Divergence struct {
+	Table     string
+	Column    string
+	Key       interface{}
+	Primary   map[string]interface{}
+	Secondary map[string]interface{}
+}
+
+// VerificationReport is the result of re-reading sampled rows from both databases
+type VerificationReport struct {
+	Checked     int
+	Divergences []Divergence
+}
+
+type writeSample struct {
+	table  string
+	column string
+	key    interface{}
+}
+
+type writeSampler struct {
+	mu      sync.Mutex
+	rate    float64
+	samples []writeSample
+}
+
+// SampleWrites records the keys of a fraction of the rows written by Records through a DualWrite
+// handle, between 0 and 1, for VerifyMirror to compare
+func (d *Database) SampleWrites(rate float64) error {
+	if d.mirror == nil {
+		return errors.New("writes are only sampled by DualWrite handles")
+	}
+	d.mirror.sampler.mu.Lock()
+	defer d.mirror.sampler.mu.Unlock()
+	d.mirror.sampler.rate = rate
+	return nil
+}
+
+// VerifyMirror flushes the secondary, then re-reads the sampled rows from both databases and
+// reports those that differ, leaving the excluded columns out of the comparison. With none given,
+// the columns the server sets itself are left out, such as a created_at with a CURRENT_TIMESTAMP
+// default or an updated_at with ON UPDATE, as the two writes happen at different times. The samples
+// are cleared
+func (d *Database) VerifyMirror(excluded ...string) (VerificationReport, error) {
+	var report VerificationReport
+	if d.mirror == nil {
+		return report, errors.New("only DualWrite handles can be verified")
+	}
+	d.mirror.sampler.mu.Lock()
+	samples := d.mirror.sampler.samples
+	d.mirror.sampler.samples = nil
+	d.mirror.sampler.mu.Unlock()
+	d.mirror.secondary.FlushAsync()
+	serverSet := make(map[string][]string)
+	for _, sample := range samples {
+		primary, err := d.sampledRow(sample)
+		if err != nil {
+			return report, err
+		}
+		secondary, err := d.mirror.secondary.sampledRow(sample)
+		if err != nil {
+			return report, err
+		}
+		report.Checked++
+		ignored := excluded
+		if len(ignored) < 1 {
+			columns, ok := serverSet[sample.table]
+			if !ok {
+				columns, err = d.serverSetColumns(sample.table)
+				if err != nil {
+					return report, err
+				}
+				serverSet[sample.table] = columns
+			}
+			ignored = columns
+		}
+		if !reflect.DeepEqual(withoutColumns(primary, ignored), withoutColumns(secondary, ignored)) {
+			report.Divergences = append(report.Divergences, Divergence{
+				Table:     sample.table,
+				Column:    sample.column,
+				Key:       sample.key,
+				Primary:   primary,
+				Secondary: secondary,
+			})
+		}
+	}
+	return report, nil
+}
+
+// finds the table's columns whose values the server sets, from a CURRENT_TIMESTAMP default, ON UPDATE
+// or a generated column expression
+func (d *Database) serverSetColumns(table string) ([]string, error) {
+	rows, err := d.QueryRaw(
+		"SELECT column_name AS column_name FROM information_schema.columns WHERE table_schema = ? AND table_name = ? "+
+			"AND (UPPER(column_default) LIKE 'CURRENT_TIMESTAMP%' OR LOWER(extra) LIKE '%on update%' OR LOWER(extra) LIKE '%generated%')",
+		[]interface{}{d.Name(), table},
+	)
+	if err != nil {
+		return nil, err
+	}
+	columns := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if column, ok := row["column_name"].(string); ok {
+			columns = append(columns, column)
+		}
+	}
+	return columns, nil
+}
+
+// copies a row without the columns; a missing row stays nil
+func withoutColumns(row map[string]interface{}, columns []string) map[string]interface{} {
+	if row == nil || len(columns) < 1 {
+		return row
+	}
+	kept := make(map[string]interface{}, len(row))
+	for column, value := range row {
+		if !contains(columns, column) {
+			kept[column] = value
+		}
+	}
+	return kept
+}
+
+func (d *Database) sampledRow(sample writeSample) (map[string]interface{}, error) {
+	rows, err := d.QueryRaw(
+		"SELECT * FROM "+quoteIdentifier(d.Name())+"."+quoteIdentifier(sample.table)+" WHERE "+quoteIdentifier(sample.column)+" = ? LIMIT 1",
+		[]interface{}{sample.key},
+	)
+	if err != nil || len(rows) < 1 {
+		return nil, err
+	}
+	return rows[0], nil
+}
+
+// samples a written row by its key property, or the insert id when it has none
+func (r *Record) sampleWrite(column string, insertID int64) {
+	if r.database.mirror == nil {
+		return
+	}
+	sampler := &r.database.mirror.sampler
+	sampler.mu.Lock()
+	defer sampler.mu.Unlock()
+	if sampler.rate <= 0 || rand.Float64() >= sampler.rate {
+		return
+	}
+	key, ok := r.properties[column]
+	if !ok || key == nil {
+		key = insertID
+	}
+	sampler.samples = append(sampler.samples, writeSample{table: r.table, column: column, key: key})
+}
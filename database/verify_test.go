@@ -0,0 +1,120 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestVerifyMirror(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	_, err := tdb.Exec("CREATE DATABASE IF NOT EXISTS verify_mirror", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	_, err = tdb.Exec("CREATE TABLE IF NOT EXISTS verify_mirror.widgets LIKE widgets", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	configs := getConfigs(false)
+	configs.Database = "verify_mirror"
+	secondary, err := Make(configs)
+	if err != nil {
+		t.Error(err)
+	}
+	defer secondary.Close()
+	if err = tdb.SampleWrites(1); err == nil {
+		t.Errorf("expected only DualWrite handles to sample writes")
+	}
+	primary := tdb.DualWrite(&secondary, AsyncOptions{})
+	if err = primary.SampleWrites(1); err != nil {
+		t.Error(err)
+	}
+	id, err := primary.MakeRecord(map[string]interface{}{"sku": "VERIFY1"}, "widgets").Create()
+	if err != nil {
+		t.Error(err)
+	}
+	secondary.FlushAsync()
+	_, err = secondary.Exec("UPDATE widgets SET sku = 'VERIFY2' WHERE id = ?", []interface{}{id})
+	if err != nil {
+		t.Error(err)
+	}
+	report, err := primary.VerifyMirror()
+	if err != nil {
+		t.Error(err)
+	}
+	if report.Checked != 1 || len(report.Divergences) != 1 {
+		t.Fatalf("expected one divergent row, got %+v", report)
+	}
+	divergence := report.Divergences[0]
+	if divergence.Table != "widgets" || divergence.Key != id || divergence.Primary["sku"] != "VERIFY1" || divergence.Secondary["sku"] != "VERIFY2" {
+		t.Errorf("unexpected divergence %+v", divergence)
+	}
+	report, err = primary.VerifyMirror()
+	if err != nil {
+		t.Error(err)
+	}
+	if report.Checked != 0 {
+		t.Errorf("expected the samples to be cleared, got %+v", report)
+	}
+}
+
+func TestVerifyMirrorMatching(t *testing.T) {
+	defer recovery(t)
+	createWidgetsTable(t)
+	_, err := tdb.Exec("CREATE DATABASE IF NOT EXISTS verify_mirror", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	_, err = tdb.Exec("CREATE TABLE IF NOT EXISTS verify_mirror.widgets LIKE widgets", nil)
+	if err != nil {
+		t.Error(err)
+	}
+	configs := getConfigs(false)
+	configs.Database = "verify_mirror"
+	secondary, err := Make(configs)
+	if err != nil {
+		t.Error(err)
+	}
+	defer secondary.Close()
+	primary := tdb.DualWrite(&secondary, AsyncOptions{FlushInterval: time.Minute})
+	if err = primary.SampleWrites(1); err != nil {
+		t.Error(err)
+	}
+	_, err = primary.MakeRecord(map[string]interface{}{"sku": "VERIFY3", "weight": 2.5}, "widgets").Create()
+	if err != nil {
+		t.Error(err)
+	}
+	// the mirrored insert lands a second later, so its timestamps differ
+	time.Sleep(1100 * time.Millisecond)
+	report, err := primary.VerifyMirror()
+	if err != nil {
+		t.Error(err)
+	}
+	if report.Checked != 1 || len(report.Divergences) != 0 {
+		t.Errorf("expected the mirrored row to match, got %+v", report)
+	}
+	_, err = primary.MakeRecord(map[string]interface{}{"sku": "VERIFY4"}, "widgets").Create()
+	if err != nil {
+		t.Error(err)
+	}
+	time.Sleep(1100 * time.Millisecond)
+	report, err = primary.VerifyMirror("description")
+	if err != nil {
+		t.Error(err)
+	}
+	if len(report.Divergences) != 1 {
+		t.Errorf("expected the timestamps to be compared when other columns are excluded, got %+v", report)
+	}
+}
+
+func TestWithoutColumns(t *testing.T) {
+	row := map[string]interface{}{"id": 1, "sku": "A", "updated_at": "now"}
+	kept := withoutColumns(row, []string{"updated_at"})
+	if len(kept) != 2 || kept["sku"] != "A" || len(row) != 3 {
+		t.Errorf("expected updated_at to be left out of a copy, got %v", kept)
+	}
+	if withoutColumns(nil, []string{"updated_at"}) != nil {
+		t.Errorf("expected a missing row to stay nil")
+	}
+}